@@ -0,0 +1,152 @@
+package sysinfo
+
+import "strconv"
+
+const (
+	CapChown = iota
+	CapDacOverride
+	CapDacReadSearch
+	CapFowner
+	CapFsetid
+	CapKill
+	CapSetgid
+	CapSetuid
+	CapSetpcap
+	CapLinuxImmutable
+	CapNetBindService
+	CapNetBroadcast
+	CapNetAdmin
+	CapNetRaw
+	CapIPCLock
+	CapIPCOwner
+	CapSysModule
+	CapSysRawio
+	CapSysChroot
+	CapSysPtrace
+	CapSysPacct
+	CapSysAdmin
+	CapSysBoot
+	CapSysNice
+	CapSysResource
+	CapSysTime
+	CapSysTTYConfig
+	CapMknod
+	CapLease
+	CapAuditWrite
+	CapAuditControl
+	CapSetfcap
+	CapMacOverride
+	CapMacAdmin
+	CapSyslog
+	CapWakeAlarm
+	CapBlockSuspend
+	CapAuditRead
+	CapPerfmon
+	CapBPF
+	CapCheckpointRestore
+)
+
+const (
+	SeccompDisabled = iota
+	SeccompStrict
+	SeccompFilter
+)
+
+var capabilityNames = []string{
+	CapChown:             "CAP_CHOWN",
+	CapDacOverride:       "CAP_DAC_OVERRIDE",
+	CapDacReadSearch:     "CAP_DAC_READ_SEARCH",
+	CapFowner:            "CAP_FOWNER",
+	CapFsetid:            "CAP_FSETID",
+	CapKill:              "CAP_KILL",
+	CapSetgid:            "CAP_SETGID",
+	CapSetuid:            "CAP_SETUID",
+	CapSetpcap:           "CAP_SETPCAP",
+	CapLinuxImmutable:    "CAP_LINUX_IMMUTABLE",
+	CapNetBindService:    "CAP_NET_BIND_SERVICE",
+	CapNetBroadcast:      "CAP_NET_BROADCAST",
+	CapNetAdmin:          "CAP_NET_ADMIN",
+	CapNetRaw:            "CAP_NET_RAW",
+	CapIPCLock:           "CAP_IPC_LOCK",
+	CapIPCOwner:          "CAP_IPC_OWNER",
+	CapSysModule:         "CAP_SYS_MODULE",
+	CapSysRawio:          "CAP_SYS_RAWIO",
+	CapSysChroot:         "CAP_SYS_CHROOT",
+	CapSysPtrace:         "CAP_SYS_PTRACE",
+	CapSysPacct:          "CAP_SYS_PACCT",
+	CapSysAdmin:          "CAP_SYS_ADMIN",
+	CapSysBoot:           "CAP_SYS_BOOT",
+	CapSysNice:           "CAP_SYS_NICE",
+	CapSysResource:       "CAP_SYS_RESOURCE",
+	CapSysTime:           "CAP_SYS_TIME",
+	CapSysTTYConfig:      "CAP_SYS_TTY_CONFIG",
+	CapMknod:             "CAP_MKNOD",
+	CapLease:             "CAP_LEASE",
+	CapAuditWrite:        "CAP_AUDIT_WRITE",
+	CapAuditControl:      "CAP_AUDIT_CONTROL",
+	CapSetfcap:           "CAP_SETFCAP",
+	CapMacOverride:       "CAP_MAC_OVERRIDE",
+	CapMacAdmin:          "CAP_MAC_ADMIN",
+	CapSyslog:            "CAP_SYSLOG",
+	CapWakeAlarm:         "CAP_WAKE_ALARM",
+	CapBlockSuspend:      "CAP_BLOCK_SUSPEND",
+	CapAuditRead:         "CAP_AUDIT_READ",
+	CapPerfmon:           "CAP_PERFMON",
+	CapBPF:               "CAP_BPF",
+	CapCheckpointRestore: "CAP_CHECKPOINT_RESTORE",
+}
+
+type CapabilitySet uint64
+
+type ProcessCapabilityInfo struct {
+	Inheritable CapabilitySet
+	Permitted   CapabilitySet
+	Effective   CapabilitySet
+	Bounding    CapabilitySet
+	Ambient     CapabilitySet
+}
+
+func (cs CapabilitySet) Has(capability int) bool {
+	if capability < 0 || capability > 63 {
+		return false
+	}
+
+	return cs&(1<<uint(capability)) != 0
+}
+
+func (cs CapabilitySet) Names() []string {
+	var names []string
+	for capability := 0; capability < 64; capability++ {
+		if cs.Has(capability) {
+			names = append(names, CapabilityName(capability))
+		}
+	}
+
+	return names
+}
+
+func CapabilityName(capability int) string {
+	if capability >= 0 && capability < len(capabilityNames) {
+		return capabilityNames[capability]
+	}
+
+	return "CAP_" + strconv.Itoa(capability)
+}
+
+func CapabilityByName(name string) (int, bool) {
+	for capability, capName := range capabilityNames {
+		if capName == name {
+			return capability, true
+		}
+	}
+
+	return 0, false
+}
+
+func (pi *ProcessInfo) Privileged() bool {
+	if pi.EffectiveUserID == 0 {
+		return true
+	}
+
+	return pi.Capabilities != nil && pi.Capabilities.Effective != 0
+}
@@ -0,0 +1,111 @@
+package sysinfo
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type CPUSet []uint64
+
+func ParseCPUSet(list string) (CPUSet, error) {
+	var set CPUSet
+
+	list = strings.TrimSpace(list)
+	if list == "" {
+		return set, nil
+	}
+
+	// lists are made of comma separated IDs and inclusive ID ranges,
+	// e.g. 0-3,8,10-11
+	for _, part := range strings.Split(list, ",") {
+		bounds := strings.SplitN(part, "-", 2)
+
+		first, err := strconv.ParseUint(bounds[0], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.ParseUint(bounds[1], 10, 64); err != nil {
+				return nil, err
+			}
+			if last < first {
+				return nil, ErrInvalidFileFormat
+			}
+		}
+
+		for id := first; id <= last; id++ {
+			set = append(set, id)
+		}
+	}
+
+	sort.Slice(set, func(i, j int) bool { return set[i] < set[j] })
+	return set, nil
+}
+
+func (s CPUSet) Contains(id uint64) bool {
+	i := sort.Search(len(s), func(i int) bool { return s[i] >= id })
+	return i < len(s) && s[i] == id
+}
+
+func (s CPUSet) SubsetOf(other CPUSet) bool {
+	for _, id := range s {
+		if !other.Contains(id) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s CPUSet) Equal(other CPUSet) bool {
+	return len(s) == len(other) && s.SubsetOf(other)
+}
+
+func (s CPUSet) String() string {
+	var parts []string
+	for i := 0; i < len(s); {
+		j := i
+		for j+1 < len(s) && s[j+1] == s[j]+1 {
+			j++
+		}
+
+		part := strconv.FormatUint(s[i], 10)
+		if j > i {
+			part += "-" + strconv.FormatUint(s[j], 10)
+		}
+
+		parts = append(parts, part)
+		i = j + 1
+	}
+
+	return strings.Join(parts, ",")
+}
+
+func (s CPUSet) mask() []uint64 {
+	mask := make([]uint64, 16)
+	for _, id := range s {
+		for int(id/64) >= len(mask) {
+			mask = append(mask, 0)
+		}
+
+		mask[id/64] |= 1 << (id % 64)
+	}
+
+	return mask
+}
+
+func cpuSetFromMask(mask []uint64) CPUSet {
+	var set CPUSet
+	for i, word := range mask {
+		for bit := uint64(0); bit < 64; bit++ {
+			if word&(1<<bit) != 0 {
+				set = append(set, uint64(i)*64+bit)
+			}
+		}
+	}
+
+	return set
+}
@@ -0,0 +1,64 @@
+package sysinfo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCPUSet(t *testing.T) {
+	tests := []struct {
+		list   string
+		set    CPUSet
+		string string
+		err    bool
+	}{
+		{"", nil, "", false},
+		{"0", CPUSet{0}, "0", false},
+		{"0-3", CPUSet{0, 1, 2, 3}, "0-3", false},
+		{"0-3,8,10-11", CPUSet{0, 1, 2, 3, 8, 10, 11}, "0-3,8,10-11", false},
+		{"8,0-1\n", CPUSet{0, 1, 8}, "0-1,8", false},
+		{"1,2,3,5", CPUSet{1, 2, 3, 5}, "1-3,5", false},
+		{"64-65,127", CPUSet{64, 65, 127}, "64-65,127", false},
+		{"3-1", nil, "", true},
+		{"a-b", nil, "", true},
+		{"0,,1", nil, "", true},
+	}
+
+	for _, test := range tests {
+		set, err := ParseCPUSet(test.list)
+		if (err != nil) != test.err {
+			t.Errorf("%q: unexpected error %v", test.list, err)
+			continue
+		}
+		if test.err {
+			continue
+		}
+
+		if !reflect.DeepEqual(set, test.set) {
+			t.Errorf("%q: expected %v, got %v", test.list, []uint64(test.set), []uint64(set))
+		}
+		if set.String() != test.string {
+			t.Errorf("%q: expected string %q, got %q", test.list, test.string, set.String())
+		}
+
+		// the string representation parses back to the same set
+		again, err := ParseCPUSet(set.String())
+		if err != nil || !reflect.DeepEqual(again, set) {
+			t.Errorf("%q: round trip gave %v, %v", test.list, []uint64(again), err)
+		}
+	}
+}
+
+func TestCPUSetMask(t *testing.T) {
+	set := CPUSet{0, 5, 63, 64, 130}
+	if got := cpuSetFromMask(set.mask()); !reflect.DeepEqual(got, set) {
+		t.Errorf("expected %v, got %v", []uint64(set), []uint64(got))
+	}
+
+	if !set.Contains(64) || set.Contains(65) {
+		t.Errorf("unexpected membership for %v", set)
+	}
+	if !(CPUSet{0, 63}).SubsetOf(set) || (CPUSet{0, 1}).SubsetOf(set) {
+		t.Errorf("unexpected subsets of %v", set)
+	}
+}
@@ -1,9 +1,12 @@
 package sysinfo
 
 import (
+	"bufio"
+	"os"
 	"os/exec"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type NodeInfo struct {
@@ -90,6 +93,35 @@ func Uptime() (float64, error) {
 	return strconv.ParseFloat(fields[0], 64)
 }
 
+func BootTime() (time.Time, error) {
+	file, err := os.Open(cpuStatPath)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || fields[0] != "btime" {
+			continue
+		}
+
+		btime, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		return time.Unix(btime, 0), nil
+	}
+
+	if err = scanner.Err(); err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Time{}, ErrInvalidFileFormat
+}
+
 func Architecture() (string, error) {
 	output, err := exec.Command("uname", "-m").Output()
 	if err != nil {
@@ -1,34 +1,71 @@
 package sysinfo
 
 import (
-	"bufio"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 )
 
 var processFile = "/proc/%d/%s"
 
+const pfKthread = 0x00200000
+
 type ProcessInfo struct {
-	ID          uint64
-	ParentID    uint64
-	Name        string
-	Path        string
-	Arguments   []string
-	State       string
-	UserID      uint64
-	GroupID     uint64
-	GroupIDs    []uint64
-	TTY         uint64
-	ThreadCount uint64
-	Priority    int64
-	Nice        int64
-	FDCount     uint64
-
-	CPU    *ProcessCPUInfo
-	Memory *ProcessMemoryInfo
+	ID                uint64
+	ParentID          uint64
+	Name              string
+	Path              string
+	Arguments         []string
+	CommandLine       string
+	KernelThread      bool
+	TitleRewritten    bool
+	State             string
+	UserID            uint64
+	EffectiveUserID   uint64
+	SavedUserID       uint64
+	FilesystemUserID  uint64
+	GroupID           uint64
+	EffectiveGroupID  uint64
+	SavedGroupID      uint64
+	FilesystemGroupID uint64
+	GroupIDs          []uint64
+	Capabilities      *ProcessCapabilityInfo
+	NoNewPrivileges   bool
+	Seccomp           uint64
+	TTY               uint64
+	ProcessGroupID    uint64
+	SessionID         uint64
+	TTYProcessGroupID int64
+	ThreadCount       uint64
+	Priority          int64
+	Nice              int64
+	RealtimePriority  uint64
+	Policy            uint64
+	LastCPU           uint64
+	MinorFaults       uint64
+	MajorFaults       uint64
+	BlockIODelay      uint64
+	ExitCode          int64
+	FDSize            uint64
+	FDCount           uint64
+	Namespaces        map[string]uint64
+	Cgroups           []*ProcessCgroupInfo
+	Container         *ProcessContainerInfo
+	AllowedCPUs       CPUSet
+	AllowedMemNodes   CPUSet
+
+	CPU       *ProcessCPUInfo
+	Memory    *ProcessMemoryInfo
+	IO        *ProcessIOInfo
+	Signals   *ProcessSignalInfo
+	Scheduler *ProcessSchedulerInfo
+
+	fields ProcessField
+	denied ProcessField
 }
 
 type ProcessMemoryInfo struct {
@@ -75,10 +112,89 @@ func (pi *ProcessInfo) MemoryUsagePercent() (float64, error) {
 }
 
 func (pi *ProcessInfo) Update() error {
+	uptime, err := Uptime()
+	if err != nil {
+		return err
+	}
+
 	if err := readProcStatusFile(pi.ID, pi); err != nil {
 		return err
 	}
-	if err := readProcStatFile(pi.ID, pi); err != nil {
+	if err := readProcStatFile(pi.ID, pi, uptime); err != nil {
+		return err
+	}
+
+	return pi.load(pi.fields|pi.denied, uptime)
+}
+
+func (pi *ProcessInfo) Load(fields ProcessField) error {
+	uptime, err := Uptime()
+	if err != nil {
+		return err
+	}
+
+	return pi.load(fields, uptime)
+}
+
+func (pi *ProcessInfo) load(fields ProcessField, uptime float64) error {
+	if fields&ProcessFieldCmdline != 0 {
+		if err := pi.loaded(ProcessFieldCmdline, readProcCmdlineFile(pi.ID, pi), false); err != nil {
+			return err
+		}
+	}
+	if fields&ProcessFieldIO != 0 {
+		if err := pi.loaded(ProcessFieldIO, readProcIOFile(pi.ID, pi, uptime), true); err != nil {
+			return err
+		}
+	}
+	if fields&ProcessFieldFDs != 0 {
+		if err := pi.loaded(ProcessFieldFDs, readProcFDDir(pi.ID, pi), true); err != nil {
+			return err
+		}
+	}
+	if fields&ProcessFieldNamespaces != 0 {
+		if err := pi.loaded(ProcessFieldNamespaces, readProcNamespaces(pi.ID, pi), true); err != nil {
+			return err
+		}
+	}
+	if fields&ProcessFieldCgroups != 0 {
+		if err := pi.loaded(ProcessFieldCgroups, readProcCgroupFile(pi.ID, pi), false); err != nil {
+			return err
+		}
+	}
+	if fields&ProcessFieldScheduler != 0 {
+		// kernels built without scheduler statistics have no schedstat file
+		err := readProcSchedstatFile(pi.ID, pi)
+		if os.IsNotExist(err) {
+			err = nil
+		}
+		if err := pi.loaded(ProcessFieldScheduler, err, false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (pi *ProcessInfo) Loaded(field ProcessField) bool {
+	return pi.fields&field == field
+}
+
+func (pi *ProcessInfo) Denied(field ProcessField) bool {
+	return pi.denied&field != 0
+}
+
+func (pi *ProcessInfo) loaded(field ProcessField, err error, allowDenied bool) error {
+	switch {
+	case err == nil:
+		pi.fields |= field
+		pi.denied &^= field
+	case allowDenied && os.IsPermission(err):
+		// the field is left unset instead of failing the whole process, so
+		// that it is not mistaken for a zero value
+		pi.fields &^= field
+		pi.denied |= field
+	default:
 		return err
 	}
 
@@ -102,48 +218,69 @@ func ProcessCPUUsagePercent(a, b *ProcessCPUInfo) float64 {
 	return 100 * float64(b.Total-a.Total) / float64(TicksPerSecond) / elapsed
 }
 
-func Process(pid uint64) (*ProcessInfo, error) {
-	proc := &ProcessInfo{CPU: &ProcessCPUInfo{}, Memory: &ProcessMemoryInfo{}}
-	if err := readProcStatusFile(pid, proc); err != nil {
+func Process(pid uint64, opts ...ProcessListOption) (*ProcessInfo, error) {
+	uptime, err := Uptime()
+	if err != nil {
 		return nil, err
 	}
-	if err := readProcStatFile(pid, proc); err != nil {
+
+	proc, err := readProcess(pid, newProcessListOptions(opts), uptime)
+	if err != nil {
 		return nil, err
 	}
-	if err := readProcCmdlineFile(pid, proc); err != nil {
-		return nil, err
+	if proc == nil {
+		return nil, ErrProcessNotFound
 	}
 
 	return proc, nil
 }
 
-func ProcessList() ([]*ProcessInfo, error) {
-	fis, err := ioutil.ReadDir("/proc")
+func ProcessList(opts ...ProcessListOption) ([]*ProcessInfo, error) {
+	scan, err := ScanProcesses(context.Background(), opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	var procs []*ProcessInfo
-	for _, fi := range fis {
-		name := fi.Name()
-		if n := name[0] - '0'; n < 0 || n > 9 {
-			continue
-		}
+	return scan.Processes, nil
+}
 
-		pid, err := strconv.ParseUint(name, 0, 64)
-		if err != nil {
-			continue
-		}
+func readProcess(pid uint64, options *processListOptions, uptime float64) (*ProcessInfo, error) {
+	proc := &ProcessInfo{
+		CPU:       &ProcessCPUInfo{},
+		Memory:    &ProcessMemoryInfo{},
+		Signals:   &ProcessSignalInfo{},
+		Scheduler: &ProcessSchedulerInfo{},
+	}
 
-		proc, err := Process(pid)
-		if err != nil {
-			continue
+	// filters are applied as soon as the information they need is
+	// available, so that discarded processes are not read further
+	if err := readProcStatusFile(pid, proc); err != nil {
+		return nil, err
+	}
+	if !options.matchStatus(proc) {
+		return nil, nil
+	}
+
+	fields := options.fields
+	if options.needsCgroups() {
+		if err := proc.load(ProcessFieldCgroups, uptime); err != nil {
+			return nil, err
+		}
+		if !options.matchCgroups(proc) {
+			return nil, nil
 		}
 
-		procs = append(procs, proc)
+		fields &^= ProcessFieldCgroups
 	}
 
-	return procs, nil
+	if err := readProcStatFile(pid, proc, uptime); err != nil {
+		return nil, err
+	}
+	if err := proc.load(fields, uptime); err != nil {
+		return nil, err
+	}
+
+	return proc, nil
 }
 
 func readProcStatusFile(pid uint64, proc *ProcessInfo) error {
@@ -153,7 +290,9 @@ func readProcStatusFile(pid uint64, proc *ProcessInfo) error {
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
+	scanner, release := newPooledScanner(file)
+	defer release()
+
 	for scanner.Scan() {
 		fields := strings.Fields(scanner.Text())
 		if len(fields) < 2 {
@@ -164,7 +303,7 @@ func readProcStatusFile(pid uint64, proc *ProcessInfo) error {
 		key := strings.ToLower(strings.TrimSuffix(fields[0], ":"))
 		switch key {
 		case "name":
-			proc.Name = strings.Trim(val, "()")
+			proc.Name = strings.TrimSpace(strings.TrimPrefix(scanner.Text(), fields[0]))
 
 		case "tgid":
 			proc.ID, err = strconv.ParseUint(val, 10, 64)
@@ -188,24 +327,118 @@ func readProcStatusFile(pid uint64, proc *ProcessInfo) error {
 			}
 
 		case "fdsize":
-			proc.FDCount, err = strconv.ParseUint(val, 10, 64)
+			proc.FDSize, err = strconv.ParseUint(val, 10, 64)
 			if err != nil {
 				return err
 			}
 
 		case "uid":
-			proc.UserID, err = strconv.ParseUint(val, 10, 64)
+			err = parseProcStatusIDs(fields[1:], &proc.UserID, &proc.EffectiveUserID,
+				&proc.SavedUserID, &proc.FilesystemUserID)
 			if err != nil {
 				return err
 			}
 
 		case "gid":
-			proc.GroupID, err = strconv.ParseUint(val, 10, 64)
+			err = parseProcStatusIDs(fields[1:], &proc.GroupID, &proc.EffectiveGroupID,
+				&proc.SavedGroupID, &proc.FilesystemGroupID)
+			if err != nil {
+				return err
+			}
+
+		case "capinh", "capprm", "capeff", "capbnd", "capamb":
+			caps, err := strconv.ParseUint(val, 16, 64)
+			if err != nil {
+				return err
+			}
+
+			if proc.Capabilities == nil {
+				proc.Capabilities = &ProcessCapabilityInfo{}
+			}
+
+			switch key {
+			case "capinh":
+				proc.Capabilities.Inheritable = CapabilitySet(caps)
+			case "capprm":
+				proc.Capabilities.Permitted = CapabilitySet(caps)
+			case "capeff":
+				proc.Capabilities.Effective = CapabilitySet(caps)
+			case "capbnd":
+				proc.Capabilities.Bounding = CapabilitySet(caps)
+			case "capamb":
+				proc.Capabilities.Ambient = CapabilitySet(caps)
+			}
+
+		case "sigq":
+			queue := strings.Split(val, "/")
+			if len(queue) != 2 {
+				return ErrInvalidFileFormat
+			}
+
+			proc.Signals.Queued, err = strconv.ParseUint(queue[0], 10, 64)
+			if err != nil {
+				return err
+			}
+
+			proc.Signals.QueueLimit, err = strconv.ParseUint(queue[1], 10, 64)
+			if err != nil {
+				return err
+			}
+
+		case "sigpnd", "shdpnd", "sigblk", "sigign", "sigcgt":
+			signals, err := strconv.ParseUint(val, 16, 64)
+			if err != nil {
+				return err
+			}
+
+			switch key {
+			case "sigpnd":
+				proc.Signals.Pending = SignalSet(signals)
+			case "shdpnd":
+				proc.Signals.SharedPending = SignalSet(signals)
+			case "sigblk":
+				proc.Signals.Blocked = SignalSet(signals)
+			case "sigign":
+				proc.Signals.Ignored = SignalSet(signals)
+			case "sigcgt":
+				proc.Signals.Caught = SignalSet(signals)
+			}
+
+		case "voluntary_ctxt_switches":
+			proc.Scheduler.VoluntaryContextSwitches, err = strconv.ParseUint(val, 10, 64)
+			if err != nil {
+				return err
+			}
+
+		case "nonvoluntary_ctxt_switches":
+			proc.Scheduler.NonvoluntaryContextSwitches, err = strconv.ParseUint(val, 10, 64)
+			if err != nil {
+				return err
+			}
+
+		case "nonewprivs":
+			proc.NoNewPrivileges = val == "1"
+
+		case "seccomp":
+			proc.Seccomp, err = strconv.ParseUint(val, 10, 64)
+			if err != nil {
+				return err
+			}
+
+		case "cpus_allowed_list":
+			proc.AllowedCPUs, err = ParseCPUSet(val)
+			if err != nil {
+				return err
+			}
+
+		case "mems_allowed_list":
+			proc.AllowedMemNodes, err = ParseCPUSet(val)
 			if err != nil {
 				return err
 			}
 
 		case "groups":
+			proc.GroupIDs = nil
 			for _, field := range fields[1:] {
 				groupID, err := strconv.ParseUint(field, 10, 64)
 				if err != nil {
@@ -278,68 +511,86 @@ func readProcStatusFile(pid uint64, proc *ProcessInfo) error {
 	return nil
 }
 
-func readProcStatFile(pid uint64, proc *ProcessInfo) error {
-	file, err := os.Open(fmt.Sprintf(processFile, pid, "stat"))
-	if err != nil {
-		return err
+func parseProcStatusIDs(fields []string, ids ...*uint64) error {
+	if len(fields) < len(ids) {
+		return ErrInvalidFileFormat
 	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		fields := strings.Fields(scanner.Text())
-		if len(fields) < 44 {
-			return ErrInvalidFileFormat
-		}
 
-		proc.TTY, err = strconv.ParseUint(fields[6], 10, 64)
-		if err != nil {
+	var err error
+	for i, id := range ids {
+		if *id, err = strconv.ParseUint(fields[i], 10, 64); err != nil {
 			return err
 		}
+	}
 
-		proc.CPU.User, err = strconv.ParseUint(fields[13], 10, 64)
-		if err != nil {
-			return err
-		}
+	return nil
+}
 
-		proc.CPU.System, err = strconv.ParseUint(fields[14], 10, 64)
-		if err != nil {
-			return err
-		}
+func readProcStatFile(pid uint64, proc *ProcessInfo, uptime float64) error {
+	file, err := os.Open(fmt.Sprintf(processFile, pid, "stat"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
 
-		proc.CPU.ChildrenUser, err = strconv.ParseUint(fields[15], 10, 64)
-		if err != nil {
-			return err
-		}
+	scanner, release := newPooledScanner(file)
+	defer release()
 
-		proc.CPU.ChildrenSystem, err = strconv.ParseUint(fields[16], 10, 64)
+	for scanner.Scan() {
+		_, fields, err := splitProcStat(scanner.Text())
 		if err != nil {
 			return err
 		}
+		if len(fields) < 42 {
+			return ErrInvalidFileFormat
+		}
 
-		proc.Priority, err = strconv.ParseInt(fields[17], 10, 64)
-		if err != nil {
-			return err
+		var flags uint64
+		for _, field := range []struct {
+			idx int
+			dst *uint64
+		}{
+			{2, &proc.ProcessGroupID},
+			{3, &proc.SessionID},
+			{4, &proc.TTY},
+			{6, &flags},
+			{7, &proc.MinorFaults},
+			{9, &proc.MajorFaults},
+			{11, &proc.CPU.User},
+			{12, &proc.CPU.System},
+			{13, &proc.CPU.ChildrenUser},
+			{14, &proc.CPU.ChildrenSystem},
+			{19, &proc.CPU.Start},
+			{36, &proc.LastCPU},
+			{37, &proc.RealtimePriority},
+			{38, &proc.Policy},
+			{39, &proc.BlockIODelay},
+			{40, &proc.CPU.Guest},
+			{41, &proc.CPU.ChildrenGuest},
+		} {
+			if *field.dst, err = strconv.ParseUint(fields[field.idx], 10, 64); err != nil {
+				return err
+			}
 		}
+		proc.KernelThread = flags&pfKthread != 0
 
-		proc.Nice, err = strconv.ParseInt(fields[18], 10, 64)
-		if err != nil {
+		if proc.TTYProcessGroupID, err = strconv.ParseInt(fields[5], 10, 64); err != nil {
 			return err
 		}
 
-		proc.CPU.Start, err = strconv.ParseUint(fields[21], 10, 64)
-		if err != nil {
+		if proc.Priority, err = strconv.ParseInt(fields[15], 10, 64); err != nil {
 			return err
 		}
 
-		proc.CPU.Guest, err = strconv.ParseUint(fields[42], 10, 64)
-		if err != nil {
+		if proc.Nice, err = strconv.ParseInt(fields[16], 10, 64); err != nil {
 			return err
 		}
 
-		proc.CPU.ChildrenGuest, err = strconv.ParseUint(fields[43], 10, 64)
-		if err != nil {
-			return err
+		// the exit code is only reported by newer kernels
+		if len(fields) > 49 {
+			if proc.ExitCode, err = strconv.ParseInt(fields[49], 10, 64); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -352,28 +603,98 @@ func readProcStatFile(pid uint64, proc *ProcessInfo) error {
 	proc.CPU.Total = proc.CPU.User + proc.CPU.System + proc.CPU.ChildrenUser +
 		proc.CPU.ChildrenSystem + proc.CPU.Guest + proc.CPU.ChildrenGuest
 
-	if proc.CPU.sysUptime, err = Uptime(); err != nil {
-		return err
-	}
+	proc.CPU.sysUptime = uptime
 
 	return nil
 }
 
+// splitProcStat splits the content of a stat file into the command name
+// and the fields following it. The command name is enclosed in parentheses
+// and may contain spaces and parentheses itself, so it ends at the last
+// closing parenthesis.
+func splitProcStat(content string) (string, []string, error) {
+	start := strings.IndexByte(content, '(')
+	end := strings.LastIndexByte(content, ')')
+	if start == -1 || end < start {
+		return "", nil, ErrInvalidFileFormat
+	}
+
+	return content[start+1 : end], strings.Fields(content[end+1:]), nil
+}
+
 func readProcCmdlineFile(pid uint64, proc *ProcessInfo) error {
-	cmd, err := readSingleValueFile(fmt.Sprintf(processFile, pid, "cmdline"))
+	content, err := ioutil.ReadFile(fmt.Sprintf(processFile, pid, "cmdline"))
 	if err != nil {
-		return nil
+		return err
 	}
 
-	fields := strings.Fields(strings.Replace(cmd, "\u0000", " ", -1))
-	if len(fields) == 0 {
-		return nil
-	}
+	cmd := string(content)
+	parseProcCmdline(cmd, proc, func(path string) bool {
+		return processFileExists(pid, path)
+	})
+
+	if cmd == "" {
+		// kernel threads have no command line
+		if !proc.KernelThread {
+			return nil
+		}
+
+		comm, err := readSingleValueFile(fmt.Sprintf(processFile, pid, "comm"))
+		if err != nil {
+			return err
+		}
 
-	proc.Path = fields[0]
-	for _, arg := range fields[1:] {
-		proc.Arguments = append(proc.Arguments, arg)
+		proc.CommandLine = "[" + comm + "]"
 	}
 
 	return nil
 }
+
+func parseProcCmdline(cmd string, proc *ProcessInfo, exists func(string) bool) {
+	proc.Path, proc.Arguments, proc.CommandLine = "", nil, ""
+	proc.TitleRewritten = false
+	if cmd == "" {
+		return
+	}
+
+	// processes which set their title (e.g. nginx, postgres) overwrite
+	// the argument vector with a single space separated string, padded
+	// with NUL bytes. The title is not an argument vector, so it is only
+	// exposed as the command line.
+	if title := strings.TrimRight(cmd, "\x00"); !strings.Contains(title, "\x00") &&
+		strings.ContainsRune(title, ' ') && strings.TrimSpace(title) != "" && !exists(title) {
+		proc.CommandLine = strings.TrimSpace(title)
+		proc.TitleRewritten = true
+		return
+	}
+
+	args := strings.Split(strings.TrimSuffix(cmd, "\x00"), "\x00")
+	proc.Path = args[0]
+	proc.Arguments = args[1:]
+	proc.CommandLine = strings.Join(args, " ")
+}
+
+func processFileExists(pid uint64, path string) bool {
+	// the path is resolved as seen by the process, relative paths against
+	// its working directory
+	base := "cwd"
+	if filepath.IsAbs(path) {
+		base = "root"
+	}
+
+	_, err := os.Stat(filepath.Join(fmt.Sprintf(processFile, pid, base), path))
+	return err == nil
+}
+
+func processError(pid uint64, err error) error {
+	switch {
+	case os.IsPermission(err):
+		return ErrPermissionDenied
+	case os.IsNotExist(err):
+		if _, serr := os.Stat(fmt.Sprintf("/proc/%d", pid)); os.IsNotExist(serr) {
+			return ErrProcessNotFound
+		}
+	}
+
+	return err
+}
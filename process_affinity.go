@@ -0,0 +1,65 @@
+package sysinfo
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+func (pi *ProcessInfo) CPUAffinity() (CPUSet, error) {
+	set, err := schedGetAffinity(pi.ID)
+	if err != nil {
+		return nil, controlError(pi.ID, "get CPU affinity of", err)
+	}
+
+	return set, nil
+}
+
+func (ti *ProcessThreadInfo) CPUAffinity() (CPUSet, error) {
+	set, err := schedGetAffinity(ti.ID)
+	if err != nil {
+		return nil, controlError(ti.ID, "get CPU affinity of", err)
+	}
+
+	return set, nil
+}
+
+func (pi *ProcessInfo) SetCPUAffinity(cpus CPUSet) error {
+	mask := cpus.mask()
+	err := forEachThread(pi.ID, func(tid uint64) error {
+		return schedSetAffinity(tid, mask)
+	})
+
+	return controlError(pi.ID, "set CPU affinity of", err)
+}
+
+func (ti *ProcessThreadInfo) SetCPUAffinity(cpus CPUSet) error {
+	return controlError(ti.ID, "set CPU affinity of", schedSetAffinity(ti.ID, cpus.mask()))
+}
+
+func schedSetAffinity(tid uint64, mask []uint64) error {
+	_, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_SETAFFINITY, uintptr(tid),
+		uintptr(len(mask)*8), uintptr(unsafe.Pointer(&mask[0])))
+	if errno != 0 {
+		return errno
+	}
+
+	return nil
+}
+
+func schedGetAffinity(tid uint64) (CPUSet, error) {
+	// the kernel rejects masks smaller than its own CPU mask, so the
+	// buffer is grown until it fits
+	for size := 16; ; size *= 2 {
+		mask := make([]uint64, size)
+		n, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_GETAFFINITY, uintptr(tid),
+			uintptr(len(mask)*8), uintptr(unsafe.Pointer(&mask[0])))
+		if errno == syscall.EINVAL && size < 1024 {
+			continue
+		}
+		if errno != 0 {
+			return nil, errno
+		}
+
+		return cpuSetFromMask(mask[:(n+7)/8]), nil
+	}
+}
@@ -0,0 +1,185 @@
+package sysinfo
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	ContainerRuntimeDocker     = "docker"
+	ContainerRuntimeContainerd = "containerd"
+	ContainerRuntimeCRIO       = "cri-o"
+	ContainerRuntimePodman     = "podman"
+	ContainerRuntimeSystemd    = "systemd"
+)
+
+const ContainerOrchestratorKubernetes = "kubernetes"
+
+type ProcessCgroupInfo struct {
+	HierarchyID uint64
+	Controllers []string
+	Path        string
+}
+
+type ProcessContainerInfo struct {
+	Runtime      string
+	ID           string
+	Orchestrator string
+}
+
+func readProcCgroupFile(pid uint64, proc *ProcessInfo) error {
+	file, err := os.Open(fmt.Sprintf(processFile, pid, "cgroup"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var cgroups []*ProcessCgroupInfo
+
+	scanner, release := newPooledScanner(file)
+	defer release()
+
+	for scanner.Scan() {
+		// hierarchy-ID:controller-list:cgroup-path
+		fields := strings.SplitN(scanner.Text(), ":", 3)
+		if len(fields) != 3 {
+			return ErrInvalidFileFormat
+		}
+
+		hierarchyID, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			return err
+		}
+
+		cgroup := &ProcessCgroupInfo{
+			HierarchyID: hierarchyID,
+			Path:        fields[2],
+		}
+
+		for _, controller := range strings.Split(fields[1], ",") {
+			if controller != "" {
+				cgroup.Controllers = append(cgroup.Controllers, controller)
+			}
+		}
+
+		cgroups = append(cgroups, cgroup)
+	}
+
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+
+	proc.Cgroups = cgroups
+	proc.Container = containerFromCgroups(cgroups)
+
+	return nil
+}
+
+func containerFromCgroups(cgroups []*ProcessCgroupInfo) *ProcessContainerInfo {
+	// container runtimes take precedence over the systemd unit which
+	// might be wrapping the container
+	var unit *ProcessContainerInfo
+	for _, cgroup := range cgroups {
+		container := containerFromCgroupPath(cgroup.Path)
+		if container == nil {
+			continue
+		}
+		if container.Runtime != ContainerRuntimeSystemd {
+			return container
+		}
+		if unit == nil {
+			unit = container
+		}
+	}
+
+	return unit
+}
+
+func containerFromCgroupPath(path string) *ProcessContainerInfo {
+	container := runtimeFromCgroupPath(path)
+	if container != nil && container.Runtime != ContainerRuntimeSystemd &&
+		strings.Contains(path, "kubepods") {
+		container.Orchestrator = ContainerOrchestratorKubernetes
+	}
+
+	return container
+}
+
+func runtimeFromCgroupPath(path string) *ProcessContainerInfo {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		segment := segments[i]
+
+		// systemd cgroup driver: <prefix>-<id>.scope
+		if scope := strings.TrimSuffix(segment, ".scope"); scope != segment {
+			for _, p := range []struct{ prefix, runtime string }{
+				{"docker-", ContainerRuntimeDocker},
+				{"cri-containerd-", ContainerRuntimeContainerd},
+				{"crio-conmon-", ""},
+				{"crio-", ContainerRuntimeCRIO},
+				{"libpod-conmon-", ""},
+				{"libpod-", ContainerRuntimePodman},
+			} {
+				if !strings.HasPrefix(scope, p.prefix) {
+					continue
+				}
+				if p.runtime == "" {
+					break
+				}
+
+				id := strings.TrimPrefix(scope, p.prefix)
+				if isContainerID(id) {
+					return &ProcessContainerInfo{Runtime: p.runtime, ID: id}
+				}
+			}
+		}
+
+		// podman with the cgroupfs driver: /libpod_parent/libpod-<id>
+		if id := strings.TrimPrefix(segment, "libpod-"); id != segment && isContainerID(id) &&
+			i > 0 && segments[i-1] == "libpod_parent" {
+			return &ProcessContainerInfo{Runtime: ContainerRuntimePodman, ID: id}
+		}
+
+		// cgroupfs driver: /<runtime>/<id>
+		if isContainerID(segment) && i > 0 {
+			switch parent := segments[i-1]; {
+			case parent == "docker":
+				return &ProcessContainerInfo{Runtime: ContainerRuntimeDocker, ID: segment}
+			case parent == "crio":
+				return &ProcessContainerInfo{Runtime: ContainerRuntimeCRIO, ID: segment}
+			case parent == "libpod_parent" || strings.HasPrefix(parent, "libpod-"):
+				return &ProcessContainerInfo{Runtime: ContainerRuntimePodman, ID: segment}
+			case parent == "k8s.io" || strings.Contains(path, "containerd"):
+				return &ProcessContainerInfo{Runtime: ContainerRuntimeContainerd, ID: segment}
+			case strings.HasPrefix(parent, "pod") || strings.HasPrefix(path, "/kubepods"):
+				// the cgroupfs driver of the kubelet does not name the runtime
+				return &ProcessContainerInfo{ID: segment}
+			}
+		}
+	}
+
+	// fall back to the innermost systemd unit
+	for i := len(segments) - 1; i >= 0; i-- {
+		if strings.HasSuffix(segments[i], ".service") || strings.HasSuffix(segments[i], ".scope") {
+			return &ProcessContainerInfo{Runtime: ContainerRuntimeSystemd, ID: segments[i]}
+		}
+	}
+
+	return nil
+}
+
+func isContainerID(id string) bool {
+	if len(id) != 64 {
+		return false
+	}
+
+	for _, c := range id {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,52 @@
+package sysinfo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestContainerFromCgroupPath(t *testing.T) {
+	id := "3f4e5d6c7b8a99887766554433221100ffeeddccbbaa00112233445566778899"
+
+	tests := []struct {
+		name      string
+		path      string
+		container *ProcessContainerInfo
+	}{
+		{"docker systemd", "/system.slice/docker-" + id + ".scope",
+			&ProcessContainerInfo{Runtime: ContainerRuntimeDocker, ID: id}},
+		{"docker cgroupfs", "/docker/" + id,
+			&ProcessContainerInfo{Runtime: ContainerRuntimeDocker, ID: id}},
+		{"containerd systemd", "/system.slice/cri-containerd-" + id + ".scope",
+			&ProcessContainerInfo{Runtime: ContainerRuntimeContainerd, ID: id}},
+		{"containerd cgroupfs", "/k8s.io/" + id,
+			&ProcessContainerInfo{Runtime: ContainerRuntimeContainerd, ID: id}},
+		{"cri-o systemd", "/machine.slice/crio-" + id + ".scope",
+			&ProcessContainerInfo{Runtime: ContainerRuntimeCRIO, ID: id}},
+		{"cri-o cgroupfs", "/crio/" + id,
+			&ProcessContainerInfo{Runtime: ContainerRuntimeCRIO, ID: id}},
+		{"cri-o conmon", "/machine.slice/crio-conmon-" + id + ".scope",
+			&ProcessContainerInfo{Runtime: ContainerRuntimeSystemd, ID: "crio-conmon-" + id + ".scope"}},
+		{"podman systemd", "/user.slice/user-1000.slice/user@1000.service/user.slice/libpod-" + id + ".scope",
+			&ProcessContainerInfo{Runtime: ContainerRuntimePodman, ID: id}},
+		{"podman cgroupfs", "/libpod_parent/libpod-" + id,
+			&ProcessContainerInfo{Runtime: ContainerRuntimePodman, ID: id}},
+		{"systemd service", "/system.slice/sshd.service",
+			&ProcessContainerInfo{Runtime: ContainerRuntimeSystemd, ID: "sshd.service"}},
+		{"systemd session", "/user.slice/user-1000.slice/session-2.scope",
+			&ProcessContainerInfo{Runtime: ContainerRuntimeSystemd, ID: "session-2.scope"}},
+		{"kubepods systemd", "/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod1a2b.slice/cri-containerd-" + id + ".scope",
+			&ProcessContainerInfo{Runtime: ContainerRuntimeContainerd, ID: id, Orchestrator: ContainerOrchestratorKubernetes}},
+		{"kubepods cgroupfs", "/kubepods/besteffort/pod1a2b/" + id,
+			&ProcessContainerInfo{ID: id, Orchestrator: ContainerOrchestratorKubernetes}},
+		{"root", "/", nil},
+		{"short id", "/docker/3f4e5d6c7b8a", nil},
+	}
+
+	for _, test := range tests {
+		container := containerFromCgroupPath(test.path)
+		if !reflect.DeepEqual(container, test.container) {
+			t.Errorf("%s: expected %+v, got %+v", test.name, test.container, container)
+		}
+	}
+}
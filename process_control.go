@@ -0,0 +1,342 @@
+package sysinfo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	IOPriorityClassNone = iota
+	IOPriorityClassRealtime
+	IOPriorityClassBestEffort
+	IOPriorityClassIdle
+)
+
+const (
+	ioprioWhoProcess = 1
+	ioprioClassShift = 13
+)
+
+type ProcessError struct {
+	ID  uint64
+	Op  string
+	Err error
+}
+
+func (e *ProcessError) Error() string {
+	return fmt.Sprintf("%s process %d: %v", e.Op, e.ID, e.Err)
+}
+
+func (e *ProcessError) Unwrap() error {
+	return e.Err
+}
+
+func (pi *ProcessInfo) Signal(sig syscall.Signal) error {
+	pidfd, err := pi.openPidfd()
+	if err == syscall.ENOSYS {
+		return controlError(pi.ID, "signal", syscall.Kill(int(pi.ID), sig))
+	}
+	if err != nil {
+		return controlError(pi.ID, "signal", err)
+	}
+	defer syscall.Close(pidfd)
+
+	_, _, errno := syscall.Syscall6(sysPidfdSendSignal, uintptr(pidfd), uintptr(sig), 0, 0, 0, 0)
+	if errno != 0 {
+		return controlError(pi.ID, "signal", errno)
+	}
+
+	return nil
+}
+
+func (pi *ProcessInfo) Terminate() error {
+	return pi.Signal(syscall.SIGTERM)
+}
+
+func (pi *ProcessInfo) Kill() error {
+	return pi.Signal(syscall.SIGKILL)
+}
+
+func (pi *ProcessInfo) Wait(ctx context.Context) error {
+	pidfd, err := pi.openPidfd()
+	if err == syscall.ENOSYS {
+		return pi.pollExit(ctx)
+	}
+	if err != nil {
+		if err == syscall.ESRCH {
+			return nil
+		}
+
+		return controlError(pi.ID, "wait", err)
+	}
+	defer syscall.Close(pidfd)
+
+	epfd, err := syscall.EpollCreate1(syscall.EPOLL_CLOEXEC)
+	if err != nil {
+		return controlError(pi.ID, "wait", err)
+	}
+	defer syscall.Close(epfd)
+
+	event := syscall.EpollEvent{Events: syscall.EPOLLIN, Fd: int32(pidfd)}
+	if err := syscall.EpollCtl(epfd, syscall.EPOLL_CTL_ADD, pidfd, &event); err != nil {
+		return controlError(pi.ID, "wait", err)
+	}
+
+	// the pidfd becomes readable when the process exits; waiting in short
+	// rounds keeps the context cancellation responsive
+	events := make([]syscall.EpollEvent, 1)
+	for {
+		// a done context still gets a non-blocking check of the process
+		msec := 100
+		if ctx.Err() != nil {
+			msec = 0
+		}
+
+		n, err := syscall.EpollWait(epfd, events, msec)
+		if err != nil && err != syscall.EINTR {
+			return controlError(pi.ID, "wait", err)
+		}
+		if n > 0 {
+			return nil
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+// TerminateTree returns the processes of the tree which could not be
+// terminated. The grace period before killing them and the wait after
+// killing them are both bounded by the timeout.
+func (pi *ProcessInfo) TerminateTree(ctx context.Context, timeout time.Duration) ([]*ProcessInfo, error) {
+	tree, err := ProcessTree()
+	if err != nil {
+		return nil, err
+	}
+
+	node := tree.Node(pi.ID)
+	if node == nil {
+		return nil, &ProcessError{ID: pi.ID, Op: "terminate", Err: ErrProcessNotFound}
+	}
+
+	procs := []*ProcessInfo{node.Process}
+	for _, descendant := range node.Descendants() {
+		procs = append(procs, descendant.Process)
+	}
+
+	signaled, survivors, errs := signalProcesses(procs, syscall.SIGTERM)
+
+	graceCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	remaining := waitProcesses(graceCtx, signaled)
+	if len(remaining) > 0 && ctx.Err() != nil {
+		errs = append(errs, ctx.Err())
+		return append(survivors, remaining...), errors.Join(errs...)
+	}
+
+	// escalate for the processes which did not exit in time. Processes
+	// stuck in uninterruptible sleep might not exit even then.
+	killed, failed, killErrs := signalProcesses(remaining, syscall.SIGKILL)
+	survivors, errs = append(survivors, failed...), append(errs, killErrs...)
+
+	killCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if alive := waitProcesses(killCtx, killed); len(alive) > 0 {
+		survivors = append(survivors, alive...)
+		errs = append(errs, killCtx.Err())
+	}
+
+	return survivors, errors.Join(errs...)
+}
+
+// The nice value, I/O priority and CPU affinity are attributes of threads,
+// so the process methods apply them to all threads of the process.
+func (pi *ProcessInfo) SetNice(nice int) error {
+	err := forEachThread(pi.ID, func(tid uint64) error {
+		return setThreadNice(tid, nice)
+	})
+
+	return controlError(pi.ID, "set nice value of", err)
+}
+
+func (ti *ProcessThreadInfo) SetNice(nice int) error {
+	return controlError(ti.ID, "set nice value of", setThreadNice(ti.ID, nice))
+}
+
+func (pi *ProcessInfo) SetIOPriority(class, level int) error {
+	err := forEachThread(pi.ID, func(tid uint64) error {
+		return setThreadIOPriority(tid, class, level)
+	})
+
+	return controlError(pi.ID, "set I/O priority of", err)
+}
+
+func (ti *ProcessThreadInfo) SetIOPriority(class, level int) error {
+	return controlError(ti.ID, "set I/O priority of", setThreadIOPriority(ti.ID, class, level))
+}
+
+func setThreadNice(tid uint64, nice int) error {
+	return syscall.Setpriority(syscall.PRIO_PROCESS, int(tid), nice)
+}
+
+func setThreadIOPriority(tid uint64, class, level int) error {
+	prio := class<<ioprioClassShift | level
+	_, _, errno := syscall.Syscall(syscall.SYS_IOPRIO_SET, ioprioWhoProcess,
+		uintptr(tid), uintptr(prio))
+	if errno != 0 {
+		return errno
+	}
+
+	return nil
+}
+
+func forEachThread(pid uint64, fn func(tid uint64) error) error {
+	// threads started while the change is applied inherit the attributes
+	// of their creator, which might not have been changed yet, so the
+	// threads are listed again until no new ones show up
+	done := map[uint64]bool{}
+	for {
+		tids, err := readProcThreadIDs(pid)
+		if err != nil {
+			if processError(pid, err) == ErrProcessNotFound {
+				return syscall.ESRCH
+			}
+
+			return err
+		}
+
+		applied := false
+		for _, tid := range tids {
+			if done[tid] {
+				continue
+			}
+
+			// threads exiting in the meantime are skipped
+			if err := fn(tid); err != nil && err != syscall.ESRCH {
+				return err
+			}
+
+			done[tid] = true
+			applied = true
+		}
+
+		if !applied {
+			return nil
+		}
+	}
+}
+
+func readProcThreadIDs(pid uint64) ([]uint64, error) {
+	dir, err := os.Open(fmt.Sprintf(processFile, pid, "task"))
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+
+	names, err := dir.Readdirnames(-1)
+	if err != nil {
+		return nil, err
+	}
+
+	tids := make([]uint64, 0, len(names))
+	for _, name := range names {
+		if tid, err := strconv.ParseUint(name, 10, 64); err == nil {
+			tids = append(tids, tid)
+		}
+	}
+
+	return tids, nil
+}
+
+func (pi *ProcessInfo) openPidfd() (int, error) {
+	fd, _, errno := syscall.Syscall(sysPidfdOpen, uintptr(pi.ID), 0, 0)
+	if errno != 0 {
+		return -1, errno
+	}
+
+	// make sure the PID was not reused by another process since the
+	// process information was read
+	if pi.CPU != nil && pi.CPU.Start != 0 {
+		proc := &ProcessInfo{ID: pi.ID, CPU: &ProcessCPUInfo{}}
+		if err := readProcStatFile(pi.ID, proc, 0); err != nil || !pi.SameProcess(proc) {
+			syscall.Close(int(fd))
+			return -1, syscall.ESRCH
+		}
+	}
+
+	return int(fd), nil
+}
+
+func (pi *ProcessInfo) pollExit(ctx context.Context) error {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		proc, err := readProcessFields(pi.ID, 0)
+		if err != nil || proc.State == "Z" {
+			return nil
+		}
+		if pi.CPU != nil && pi.CPU.Start != 0 && !pi.SameProcess(proc) {
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func waitProcesses(ctx context.Context, procs []*ProcessInfo) []*ProcessInfo {
+	var alive []*ProcessInfo
+	for _, proc := range procs {
+		if err := proc.Wait(ctx); err != nil {
+			alive = append(alive, proc)
+		}
+	}
+
+	return alive
+}
+
+func signalProcesses(procs []*ProcessInfo, sig syscall.Signal) (signaled, failed []*ProcessInfo, errs []error) {
+	// a failure to signal one process does not prevent signaling the others
+	for _, proc := range procs {
+		err := proc.Signal(sig)
+		switch {
+		case err == nil:
+			signaled = append(signaled, proc)
+		case !isProcessNotFound(err):
+			failed = append(failed, proc)
+			errs = append(errs, err)
+		}
+	}
+
+	return signaled, failed, errs
+}
+
+func isProcessNotFound(err error) bool {
+	perr, ok := err.(*ProcessError)
+	return ok && perr.Err == ErrProcessNotFound
+}
+
+func controlError(pid uint64, op string, err error) error {
+	switch err {
+	case nil:
+		return nil
+	case syscall.ESRCH:
+		err = ErrProcessNotFound
+	case syscall.EPERM, syscall.EACCES:
+		err = ErrPermissionDenied
+	}
+
+	return &ProcessError{ID: pid, Op: op, Err: err}
+}
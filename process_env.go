@@ -0,0 +1,61 @@
+package sysinfo
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+const deletedSuffix = " (deleted)"
+
+func (pi *ProcessInfo) Environment() (map[string]string, error) {
+	content, err := ioutil.ReadFile(fmt.Sprintf(processFile, pi.ID, "environ"))
+	if err != nil {
+		return nil, processError(pi.ID, err)
+	}
+
+	env := map[string]string{}
+	for _, entry := range strings.Split(string(content), "\x00") {
+		if entry == "" {
+			continue
+		}
+
+		kv := strings.SplitN(entry, "=", 2)
+		if len(kv) == 1 {
+			env[kv[0]] = ""
+			continue
+		}
+
+		env[kv[0]] = kv[1]
+	}
+
+	return env, nil
+}
+
+func (pi *ProcessInfo) WorkingDirectory() (string, error) {
+	path, _, err := readProcLink(pi.ID, "cwd")
+	return path, err
+}
+
+func (pi *ProcessInfo) RootDirectory() (string, error) {
+	path, _, err := readProcLink(pi.ID, "root")
+	return path, err
+}
+
+func (pi *ProcessInfo) Executable() (path string, deleted bool, err error) {
+	return readProcLink(pi.ID, "exe")
+}
+
+func readProcLink(pid uint64, name string) (string, bool, error) {
+	target, err := os.Readlink(fmt.Sprintf(processFile, pid, name))
+	if err != nil {
+		return "", false, processError(pid, err)
+	}
+
+	if strings.HasSuffix(target, deletedSuffix) {
+		return strings.TrimSuffix(target, deletedSuffix), true, nil
+	}
+
+	return target, false, nil
+}
@@ -0,0 +1,163 @@
+package sysinfo
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	FileTypeFile      = "file"
+	FileTypeSocket    = "socket"
+	FileTypePipe      = "pipe"
+	FileTypeAnonInode = "anon_inode"
+	FileTypeEventFD   = "eventfd"
+)
+
+type ProcessFileInfo struct {
+	FD       uint64
+	Target   string
+	Type     string
+	Inode    uint64
+	Flags    uint64
+	Position uint64
+	MountID  uint64
+}
+
+func (pi *ProcessInfo) OpenFiles() ([]*ProcessFileInfo, error) {
+	names, err := readProcFDNames(pi.ID)
+	if err != nil {
+		return nil, processError(pi.ID, err)
+	}
+
+	var files []*ProcessFileInfo
+	for _, name := range names {
+		fd, err := strconv.ParseUint(name, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		target, err := os.Readlink(fmt.Sprintf(processFile, pi.ID, "fd/"+name))
+		if err != nil {
+			// the descriptor was closed after the directory was read
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return nil, processError(pi.ID, err)
+		}
+
+		file := &ProcessFileInfo{FD: fd, Target: target}
+		file.Type, file.Inode = parseFDTarget(target)
+
+		if err := readProcFDInfoFile(pi.ID, file); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return nil, processError(pi.ID, err)
+		}
+
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+func parseFDTarget(target string) (string, uint64) {
+	var fileType, inode string
+	switch {
+	case strings.HasPrefix(target, "socket:["):
+		fileType, inode = FileTypeSocket, target[len("socket:["):]
+	case strings.HasPrefix(target, "pipe:["):
+		fileType, inode = FileTypePipe, target[len("pipe:["):]
+	case target == "anon_inode:[eventfd]":
+		return FileTypeEventFD, 0
+	case strings.HasPrefix(target, "anon_inode:"):
+		return FileTypeAnonInode, 0
+	default:
+		return FileTypeFile, 0
+	}
+
+	ino, err := strconv.ParseUint(strings.TrimSuffix(inode, "]"), 10, 64)
+	if err != nil {
+		return fileType, 0
+	}
+
+	return fileType, ino
+}
+
+func readProcFDNames(pid uint64) ([]string, error) {
+	dir, err := os.Open(fmt.Sprintf(processFile, pid, "fd"))
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+
+	return dir.Readdirnames(-1)
+}
+
+func readProcFDDir(pid uint64, proc *ProcessInfo) error {
+	names, err := readProcFDNames(pid)
+	if err != nil {
+		return err
+	}
+
+	proc.FDCount = uint64(len(names))
+	return nil
+}
+
+func readProcFDInfoFile(pid uint64, file *ProcessFileInfo) error {
+	f, err := os.Open(fmt.Sprintf(processFile, pid, "fdinfo/"+strconv.FormatUint(file.FD, 10)))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		key := strings.TrimSuffix(fields[0], ":")
+		switch key {
+		case "pos":
+			file.Position, err = strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return err
+			}
+
+		case "flags":
+			file.Flags, err = strconv.ParseUint(fields[1], 8, 64)
+			if err != nil {
+				return err
+			}
+
+		case "mnt_id":
+			file.MountID, err = strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return err
+			}
+
+		case "ino":
+			if file.Inode != 0 {
+				continue
+			}
+
+			file.Inode, err = strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+
+	return nil
+}
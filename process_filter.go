@@ -0,0 +1,160 @@
+package sysinfo
+
+import (
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+type ProcessField uint64
+
+const (
+	ProcessFieldCmdline ProcessField = 1 << iota
+	ProcessFieldIO
+	ProcessFieldFDs
+	ProcessFieldNamespaces
+	ProcessFieldCgroups
+	ProcessFieldScheduler
+
+	ProcessFieldAll = ProcessFieldCmdline | ProcessFieldIO | ProcessFieldFDs |
+		ProcessFieldNamespaces | ProcessFieldCgroups | ProcessFieldScheduler
+)
+
+type ProcessListOption func(*processListOptions)
+
+type processListOptions struct {
+	fields        ProcessField
+	concurrency   int
+	statusFilters []func(*ProcessInfo) bool
+	cgroupFilters []func(*ProcessInfo) bool
+}
+
+func WithFields(fields ProcessField) ProcessListOption {
+	return func(o *processListOptions) {
+		o.fields = fields
+	}
+}
+
+func WithConcurrency(n int) ProcessListOption {
+	return func(o *processListOptions) {
+		o.concurrency = n
+	}
+}
+
+func WithUserID(uid uint64) ProcessListOption {
+	return func(o *processListOptions) {
+		o.statusFilters = append(o.statusFilters, func(proc *ProcessInfo) bool {
+			return proc.UserID == uid
+		})
+	}
+}
+
+func WithEffectiveUserID(uid uint64) ProcessListOption {
+	return func(o *processListOptions) {
+		o.statusFilters = append(o.statusFilters, func(proc *ProcessInfo) bool {
+			return proc.EffectiveUserID == uid
+		})
+	}
+}
+
+func WithGroupID(gid uint64) ProcessListOption {
+	return func(o *processListOptions) {
+		o.statusFilters = append(o.statusFilters, func(proc *ProcessInfo) bool {
+			return proc.GroupID == gid
+		})
+	}
+}
+
+func WithName(name string) ProcessListOption {
+	return func(o *processListOptions) {
+		o.statusFilters = append(o.statusFilters, func(proc *ProcessInfo) bool {
+			return proc.Name == name
+		})
+	}
+}
+
+func WithNameRegexp(re *regexp.Regexp) ProcessListOption {
+	return func(o *processListOptions) {
+		o.statusFilters = append(o.statusFilters, func(proc *ProcessInfo) bool {
+			return re.MatchString(proc.Name)
+		})
+	}
+}
+
+func WithState(states ...string) ProcessListOption {
+	return func(o *processListOptions) {
+		o.statusFilters = append(o.statusFilters, func(proc *ProcessInfo) bool {
+			for _, state := range states {
+				if proc.State == state {
+					return true
+				}
+			}
+
+			return false
+		})
+	}
+}
+
+func WithParentID(ppid uint64) ProcessListOption {
+	return func(o *processListOptions) {
+		o.statusFilters = append(o.statusFilters, func(proc *ProcessInfo) bool {
+			return proc.ParentID == ppid
+		})
+	}
+}
+
+func WithCgroup(path string) ProcessListOption {
+	path = "/" + strings.Trim(path, "/")
+
+	return func(o *processListOptions) {
+		o.cgroupFilters = append(o.cgroupFilters, func(proc *ProcessInfo) bool {
+			for _, cgroup := range proc.Cgroups {
+				if cgroup.Path == path || path == "/" ||
+					strings.HasPrefix(cgroup.Path, path+"/") {
+					return true
+				}
+			}
+
+			return false
+		})
+	}
+}
+
+func newProcessListOptions(opts []ProcessListOption) *processListOptions {
+	options := &processListOptions{
+		fields:      ProcessFieldCmdline,
+		concurrency: runtime.NumCPU(),
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.concurrency < 1 {
+		options.concurrency = 1
+	}
+
+	return options
+}
+
+func (o *processListOptions) needsCgroups() bool {
+	return o.fields&ProcessFieldCgroups != 0 || len(o.cgroupFilters) > 0
+}
+
+func (o *processListOptions) matchStatus(proc *ProcessInfo) bool {
+	for _, match := range o.statusFilters {
+		if !match(proc) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (o *processListOptions) matchCgroups(proc *ProcessInfo) bool {
+	for _, match := range o.cgroupFilters {
+		if !match(proc) {
+			return false
+		}
+	}
+
+	return true
+}
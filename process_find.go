@@ -0,0 +1,198 @@
+package sysinfo
+
+import (
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	MatchExact = iota
+	MatchPrefix
+	MatchRegexp
+)
+
+// the kernel truncates process names to 15 characters
+const processNameLen = 15
+
+// User matches the effective user ID like pgrep -u, RealUser the real user
+// ID like pgrep -U. Group matches the real group ID.
+type ProcessQuery struct {
+	Name        string
+	Executable  string
+	CommandLine string
+	User        string
+	RealUser    string
+	Group       string
+	Mode        int
+}
+
+type processMatcher func(string) bool
+
+func FindProcesses(query *ProcessQuery, opts ...ProcessListOption) ([]*ProcessInfo, error) {
+	if query == nil {
+		return nil, ErrInvalidQuery
+	}
+
+	options := append([]ProcessListOption{WithFields(ProcessFieldCmdline)}, opts...)
+	options = append(options, func(o *processListOptions) {
+		o.fields |= ProcessFieldCmdline
+	})
+
+	// users and groups are resolved once and filtered on while scanning
+	if query.User != "" {
+		uid, err := parseUserQuery(query.User)
+		if err != nil {
+			return nil, err
+		}
+
+		options = append(options, WithEffectiveUserID(uid))
+	}
+	if query.RealUser != "" {
+		uid, err := parseUserQuery(query.RealUser)
+		if err != nil {
+			return nil, err
+		}
+
+		options = append(options, WithUserID(uid))
+	}
+	if query.Group != "" {
+		gid, err := parseGroupQuery(query.Group)
+		if err != nil {
+			return nil, err
+		}
+
+		options = append(options, WithGroupID(gid))
+	}
+
+	matchName, err := newProcessMatcher(query.Name, query.Mode)
+	if err != nil {
+		return nil, err
+	}
+
+	matchExecutable, err := newProcessMatcher(query.Executable, query.Mode)
+	if err != nil {
+		return nil, err
+	}
+
+	matchCommandLine, err := newProcessMatcher(query.CommandLine, query.Mode)
+	if err != nil {
+		return nil, err
+	}
+
+	procs, err := ProcessList(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*ProcessInfo
+	for _, proc := range procs {
+		if matchName != nil && !matchProcessName(proc, matchName) {
+			continue
+		}
+		if matchCommandLine != nil && !matchCommandLine(proc.CommandLine) {
+			continue
+		}
+		if matchExecutable != nil {
+			path, _, err := proc.Executable()
+			if err != nil || !matchExecutable(path) {
+				continue
+			}
+		}
+
+		matches = append(matches, proc)
+	}
+
+	return matches, nil
+}
+
+func PidOf(name string) ([]uint64, error) {
+	procs, err := FindProcesses(&ProcessQuery{Name: name, Mode: MatchExact})
+	if err != nil {
+		return nil, err
+	}
+
+	pids := make([]uint64, 0, len(procs))
+	for _, proc := range procs {
+		pids = append(pids, proc.ID)
+	}
+
+	return pids, nil
+}
+
+func newProcessMatcher(pattern string, mode int) (processMatcher, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+
+	switch mode {
+	case MatchPrefix:
+		return func(value string) bool {
+			return strings.HasPrefix(value, pattern)
+		}, nil
+	case MatchRegexp:
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		return re.MatchString, nil
+	}
+
+	return func(value string) bool {
+		return value == pattern
+	}, nil
+}
+
+func matchProcessName(proc *ProcessInfo, match processMatcher) bool {
+	if match(proc.Name) {
+		return true
+	}
+
+	if len(proc.Name) != processNameLen || proc.KernelThread {
+		return false
+	}
+
+	// truncated names are completed from the command line, where scripts
+	// are named by the first argument of their interpreter
+	candidates := []string{proc.Path}
+	if len(proc.Arguments) > 0 {
+		candidates = append(candidates, proc.Arguments[0])
+	}
+
+	for _, candidate := range candidates {
+		base := filepath.Base(candidate)
+		if candidate != "" && strings.HasPrefix(base, proc.Name) && match(base) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseUserQuery(user string) (uint64, error) {
+	if uid, err := strconv.ParseUint(user, 10, 64); err == nil {
+		return uid, nil
+	}
+
+	u, err := UserByName(user)
+	if err != nil {
+		return 0, err
+	}
+
+	return u.ID, nil
+}
+
+func parseGroupQuery(group string) (uint64, error) {
+	if gid, err := strconv.ParseUint(group, 10, 64); err == nil {
+		return gid, nil
+	}
+
+	g, err := GroupByName(group)
+	if err != nil {
+		return 0, err
+	}
+
+	return g.ID, nil
+}
@@ -0,0 +1,88 @@
+package sysinfo
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type ProcessIOInfo struct {
+	ReadChars           uint64
+	WriteChars          uint64
+	ReadSyscalls        uint64
+	WriteSyscalls       uint64
+	ReadBytes           uint64
+	WriteBytes          uint64
+	CancelledWriteBytes uint64
+
+	sysUptime float64
+}
+
+func ProcessIORate(a, b *ProcessIOInfo) (readRate, writeRate float64) {
+	if a == nil || b == nil || (a.sysUptime == 0 && b.sysUptime == 0) {
+		return 0.0, 0.0
+	}
+
+	if a.sysUptime > b.sysUptime {
+		a, b = b, a
+	}
+
+	elapsed := b.sysUptime - a.sysUptime
+	if elapsed == 0 {
+		elapsed = b.sysUptime
+	}
+
+	return float64(b.ReadBytes-a.ReadBytes) / elapsed,
+		float64(b.WriteBytes-a.WriteBytes) / elapsed
+}
+
+func readProcIOFile(pid uint64, proc *ProcessInfo, uptime float64) error {
+	file, err := os.Open(fmt.Sprintf(processFile, pid, "io"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	io := &ProcessIOInfo{sysUptime: uptime}
+
+	scanner, release := newPooledScanner(file)
+	defer release()
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			return ErrInvalidFileFormat
+		}
+
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return err
+		}
+
+		key := strings.TrimSuffix(fields[0], ":")
+		switch key {
+		case "rchar":
+			io.ReadChars = value
+		case "wchar":
+			io.WriteChars = value
+		case "syscr":
+			io.ReadSyscalls = value
+		case "syscw":
+			io.WriteSyscalls = value
+		case "read_bytes":
+			io.ReadBytes = value
+		case "write_bytes":
+			io.WriteBytes = value
+		case "cancelled_write_bytes":
+			io.CancelledWriteBytes = value
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+
+	proc.IO = io
+	return nil
+}
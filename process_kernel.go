@@ -0,0 +1,172 @@
+package sysinfo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// the system calls added after the unified system call table share the same
+// numbers on all architectures
+const (
+	sysPidfdSendSignal = 424
+	sysPidfdOpen       = 434
+)
+
+var commonSyscallNames = map[uint64]string{
+	sysPidfdSendSignal: "pidfd_send_signal",
+	425:                "io_uring_setup",
+	426:                "io_uring_enter",
+	427:                "io_uring_register",
+	428:                "open_tree",
+	429:                "move_mount",
+	430:                "fsopen",
+	431:                "fsconfig",
+	432:                "fsmount",
+	433:                "fspick",
+	sysPidfdOpen:       "pidfd_open",
+	435:                "clone3",
+	436:                "close_range",
+	437:                "openat2",
+	438:                "pidfd_getfd",
+	439:                "faccessat2",
+	440:                "process_madvise",
+	441:                "epoll_pwait2",
+	442:                "mount_setattr",
+	443:                "quotactl_fd",
+	444:                "landlock_create_ruleset",
+	445:                "landlock_add_rule",
+	446:                "landlock_restrict_self",
+	447:                "memfd_secret",
+	448:                "process_mrelease",
+	449:                "futex_waitv",
+	450:                "set_mempolicy_home_node",
+	451:                "cachestat",
+	452:                "fchmodat2",
+	453:                "map_shadow_stack",
+	454:                "futex_wake",
+	455:                "futex_wait",
+	456:                "futex_requeue",
+	457:                "statmount",
+	458:                "listmount",
+	459:                "lsm_get_self_attr",
+	460:                "lsm_set_self_attr",
+	461:                "lsm_list_modules",
+	462:                "mseal",
+}
+
+type ProcessSyscallInfo struct {
+	Running        bool
+	InSyscall      bool
+	Number         uint64
+	Name           string
+	Arguments      []uint64
+	StackPointer   uint64
+	ProgramCounter uint64
+}
+
+// SyscallName resolves system call numbers of the 386, amd64, arm and arm64
+// architectures. On other architectures only the numbers shared by all of
+// them, from 424 onwards, are resolved and an empty name is returned for
+// the rest.
+func SyscallName(number uint64) string {
+	if name, ok := syscallNames[number]; ok {
+		return name
+	}
+
+	return commonSyscallNames[number]
+}
+
+func (pi *ProcessInfo) WaitChannel() (string, error) {
+	wchan, err := readSingleValueFile(fmt.Sprintf(processFile, pi.ID, "wchan"))
+	if err != nil {
+		return "", processError(pi.ID, err)
+	}
+
+	// running processes and processes which cannot be inspected by the
+	// caller are reported as not waiting
+	if wchan == "0" {
+		return "", nil
+	}
+
+	return wchan, nil
+}
+
+func (pi *ProcessInfo) Syscall() (*ProcessSyscallInfo, error) {
+	content, err := readSingleValueFile(fmt.Sprintf(processFile, pi.ID, "syscall"))
+	if err != nil {
+		return nil, processError(pi.ID, err)
+	}
+
+	return parseProcSyscall(content)
+}
+
+func (pi *ProcessInfo) KernelStack() ([]string, error) {
+	content, err := readSingleValueFile(fmt.Sprintf(processFile, pi.ID, "stack"))
+	if err != nil {
+		return nil, processError(pi.ID, err)
+	}
+
+	// each frame is printed as [<address>] function+offset/size and the
+	// address is hidden from unprivileged readers anyway
+	var stack []string
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.Index(line, "] "); i >= 0 {
+			line = line[i+2:]
+		}
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+
+		stack = append(stack, line)
+	}
+
+	return stack, nil
+}
+
+func parseProcSyscall(content string) (*ProcessSyscallInfo, error) {
+	if content == "running" {
+		return &ProcessSyscallInfo{Running: true}, nil
+	}
+
+	fields := strings.Fields(content)
+	if len(fields) != 3 && len(fields) != 9 {
+		return nil, ErrInvalidFileFormat
+	}
+
+	values := make([]uint64, len(fields)-1)
+	for i, field := range fields[1:] {
+		value, err := strconv.ParseUint(strings.TrimPrefix(field, "0x"), 16, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		values[i] = value
+	}
+
+	info := &ProcessSyscallInfo{
+		StackPointer:   values[len(values)-2],
+		ProgramCounter: values[len(values)-1],
+	}
+
+	// processes blocked outside of a system call report -1 followed by the
+	// stack pointer and program counter only
+	if fields[0] == "-1" {
+		return info, nil
+	}
+
+	number, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) != 9 {
+		return nil, ErrInvalidFileFormat
+	}
+
+	info.InSyscall = true
+	info.Number = number
+	info.Name = SyscallName(number)
+	info.Arguments = values[:6]
+
+	return info, nil
+}
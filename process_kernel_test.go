@@ -0,0 +1,52 @@
+package sysinfo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseProcSyscall(t *testing.T) {
+	tests := []struct {
+		content string
+		info    *ProcessSyscallInfo
+		err     bool
+	}{
+		{"running", &ProcessSyscallInfo{Running: true}, false},
+		{"-1 0x7ffd5a1c2e48 0x7f3c1a2b3c4d", &ProcessSyscallInfo{
+			StackPointer:   0x7ffd5a1c2e48,
+			ProgramCounter: 0x7f3c1a2b3c4d,
+		}, false},
+		{"434 0x1 0x0 0x0 0x0 0x0 0x0 0x7ffd5a1c2e48 0x7f3c1a2b3c4d", &ProcessSyscallInfo{
+			InSyscall:      true,
+			Number:         434,
+			Name:           "pidfd_open",
+			Arguments:      []uint64{1, 0, 0, 0, 0, 0},
+			StackPointer:   0x7ffd5a1c2e48,
+			ProgramCounter: 0x7f3c1a2b3c4d,
+		}, false},
+		{"", nil, true},
+		{"7 0x1 0x2", nil, true},
+		{"-1 0x7ffd5a1c2e48", nil, true},
+		{"7 0x1 0x2 0x3 0x4 0x5 0xzz 0x7 0x8", nil, true},
+	}
+
+	for _, test := range tests {
+		info, err := parseProcSyscall(test.content)
+		if (err != nil) != test.err {
+			t.Errorf("%q: unexpected error %v", test.content, err)
+			continue
+		}
+		if !reflect.DeepEqual(info, test.info) {
+			t.Errorf("%q: expected %+v, got %+v", test.content, test.info, info)
+		}
+	}
+}
+
+func TestSyscallName(t *testing.T) {
+	if name := SyscallName(sysPidfdSendSignal); name != "pidfd_send_signal" {
+		t.Errorf("expected pidfd_send_signal, got %q", name)
+	}
+	if name := SyscallName(100000); name != "" {
+		t.Errorf("expected no name, got %q", name)
+	}
+}
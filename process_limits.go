@@ -0,0 +1,150 @@
+package sysinfo
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+const LimitUnlimited = ^uint64(0)
+
+const (
+	LimitCPUTime = iota
+	LimitFileSize
+	LimitDataSize
+	LimitStackSize
+	LimitCoreFileSize
+	LimitResidentSet
+	LimitProcesses
+	LimitOpenFiles
+	LimitLockedMemory
+	LimitAddressSpace
+	LimitFileLocks
+	LimitPendingSignals
+	LimitMsgqueueSize
+	LimitNicePriority
+	LimitRealtimePriority
+	LimitRealtimeTimeout
+)
+
+var limitNames = []string{
+	LimitCPUTime:          "Max cpu time",
+	LimitFileSize:         "Max file size",
+	LimitDataSize:         "Max data size",
+	LimitStackSize:        "Max stack size",
+	LimitCoreFileSize:     "Max core file size",
+	LimitResidentSet:      "Max resident set",
+	LimitProcesses:        "Max processes",
+	LimitOpenFiles:        "Max open files",
+	LimitLockedMemory:     "Max locked memory",
+	LimitAddressSpace:     "Max address space",
+	LimitFileLocks:        "Max file locks",
+	LimitPendingSignals:   "Max pending signals",
+	LimitMsgqueueSize:     "Max msgqueue size",
+	LimitNicePriority:     "Max nice priority",
+	LimitRealtimePriority: "Max realtime priority",
+	LimitRealtimeTimeout:  "Max realtime timeout",
+}
+
+type ProcessLimitInfo struct {
+	Resource int
+	Name     string
+	Soft     uint64
+	Hard     uint64
+	Unit     string
+}
+
+func (li *ProcessLimitInfo) SoftUnlimited() bool {
+	return li.Soft == LimitUnlimited
+}
+
+func (li *ProcessLimitInfo) HardUnlimited() bool {
+	return li.Hard == LimitUnlimited
+}
+
+func (pi *ProcessInfo) Limits() (map[int]*ProcessLimitInfo, error) {
+	limits, err := readProcLimitsFile(pi.ID)
+	if err != nil {
+		return nil, processError(pi.ID, err)
+	}
+
+	return limits, nil
+}
+
+func (pi *ProcessInfo) SetLimit(resource int, soft, hard uint64) error {
+	if resource < 0 || resource >= len(limitNames) {
+		return syscall.EINVAL
+	}
+
+	limit := syscall.Rlimit{Cur: soft, Max: hard}
+	_, _, errno := syscall.Syscall6(syscall.SYS_PRLIMIT64, uintptr(pi.ID),
+		uintptr(resource), uintptr(unsafe.Pointer(&limit)), 0, 0, 0)
+
+	if errno != 0 {
+		return controlError(pi.ID, "set limit of", errno)
+	}
+
+	return nil
+}
+
+func readProcLimitsFile(pid uint64) (map[int]*ProcessLimitInfo, error) {
+	file, err := os.Open(fmt.Sprintf(processFile, pid, "limits"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	limits := map[int]*ProcessLimitInfo{}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		// the limit names contain spaces, so they are matched as prefixes
+		// instead of splitting the line into fields
+		for resource, name := range limitNames {
+			if !strings.HasPrefix(line, name) {
+				continue
+			}
+
+			fields := strings.Fields(line[len(name):])
+			if len(fields) < 2 {
+				return nil, ErrInvalidFileFormat
+			}
+
+			limit := &ProcessLimitInfo{Resource: resource, Name: name}
+			if limit.Soft, err = parseLimitValue(fields[0]); err != nil {
+				return nil, err
+			}
+
+			if limit.Hard, err = parseLimitValue(fields[1]); err != nil {
+				return nil, err
+			}
+
+			if len(fields) > 2 {
+				limit.Unit = fields[2]
+			}
+
+			limits[resource] = limit
+			break
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return limits, nil
+}
+
+func parseLimitValue(value string) (uint64, error) {
+	if value == "unlimited" {
+		return LimitUnlimited, nil
+	}
+
+	return strconv.ParseUint(value, 10, 64)
+}
@@ -0,0 +1,251 @@
+package sysinfo
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type ProcessMapInfo struct {
+	StartAddress uint64
+	EndAddress   uint64
+	Permissions  string
+	Offset       uint64
+	Device       string
+	Inode        uint64
+	Path         string
+
+	Details *ProcessMapDetails
+}
+
+type ProcessMapDetails struct {
+	Size               uint64
+	KernelPageSize     uint64
+	MMUPageSize        uint64
+	Resident           uint64
+	Proportional       uint64
+	SharedClean        uint64
+	SharedDirty        uint64
+	PrivateClean       uint64
+	PrivateDirty       uint64
+	Referenced         uint64
+	Anonymous          uint64
+	AnonymousHugePages uint64
+	Swap               uint64
+	SwapProportional   uint64
+	Locked             uint64
+	Flags              []string
+}
+
+func (mi *ProcessMapInfo) Size() uint64 {
+	return mi.EndAddress - mi.StartAddress
+}
+
+func (mi *ProcessMapInfo) Readable() bool {
+	return len(mi.Permissions) > 0 && mi.Permissions[0] == 'r'
+}
+
+func (mi *ProcessMapInfo) Writable() bool {
+	return len(mi.Permissions) > 1 && mi.Permissions[1] == 'w'
+}
+
+func (mi *ProcessMapInfo) Executable() bool {
+	return len(mi.Permissions) > 2 && mi.Permissions[2] == 'x'
+}
+
+func (mi *ProcessMapInfo) Shared() bool {
+	return len(mi.Permissions) > 3 && mi.Permissions[3] == 's'
+}
+
+func (pi *ProcessInfo) Maps() ([]*ProcessMapInfo, error) {
+	maps, err := readProcMapsFile(pi.ID, "maps")
+	if err != nil {
+		return nil, processError(pi.ID, err)
+	}
+
+	return maps, nil
+}
+
+func (pi *ProcessInfo) DetailedMaps() ([]*ProcessMapInfo, error) {
+	maps, err := readProcMapsFile(pi.ID, "smaps")
+	if err != nil {
+		return nil, processError(pi.ID, err)
+	}
+
+	return maps, nil
+}
+
+func GroupMapsByPath(maps []*ProcessMapInfo) map[string][]*ProcessMapInfo {
+	groups := map[string][]*ProcessMapInfo{}
+	for _, m := range maps {
+		groups[m.Path] = append(groups[m.Path], m)
+	}
+
+	return groups
+}
+
+func readProcMapsFile(pid uint64, name string) ([]*ProcessMapInfo, error) {
+	file, err := os.Open(fmt.Sprintf(processFile, pid, name))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var maps []*ProcessMapInfo
+	var current *ProcessMapInfo
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		// smaps detail lines start with a "Key:" field, mapping headers
+		// start with an address range.
+		if strings.HasSuffix(fields[0], ":") {
+			if current == nil {
+				return nil, ErrInvalidFileFormat
+			}
+
+			if err := parseProcMapDetail(current.Details, fields); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		current, err = parseProcMapLine(line)
+		if err != nil {
+			return nil, err
+		}
+		if name == "smaps" {
+			current.Details = &ProcessMapDetails{}
+		}
+
+		maps = append(maps, current)
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return maps, nil
+}
+
+func parseProcMapLine(line string) (*ProcessMapInfo, error) {
+	// The first five columns are whitespace separated. The path takes up
+	// the rest of the line and may contain spaces.
+	var fields [5]string
+	for i := range fields {
+		line = strings.TrimLeft(line, " \t")
+		idx := strings.IndexAny(line, " \t")
+		if idx == -1 {
+			if i != len(fields)-1 {
+				return nil, ErrInvalidFileFormat
+			}
+
+			idx = len(line)
+		}
+
+		fields[i], line = line[:idx], line[idx:]
+	}
+
+	addresses := strings.Split(fields[0], "-")
+	if len(addresses) != 2 {
+		return nil, ErrInvalidFileFormat
+	}
+
+	var err error
+	m := &ProcessMapInfo{
+		Permissions: fields[1],
+		Device:      fields[3],
+		Path:        strings.TrimSpace(line),
+	}
+
+	if m.StartAddress, err = strconv.ParseUint(addresses[0], 16, 64); err != nil {
+		return nil, err
+	}
+
+	if m.EndAddress, err = strconv.ParseUint(addresses[1], 16, 64); err != nil {
+		return nil, err
+	}
+
+	if m.Offset, err = strconv.ParseUint(fields[2], 16, 64); err != nil {
+		return nil, err
+	}
+
+	if m.Inode, err = strconv.ParseUint(fields[4], 10, 64); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func parseProcMapDetail(details *ProcessMapDetails, fields []string) error {
+	if details == nil {
+		return ErrInvalidFileFormat
+	}
+
+	key := strings.ToLower(strings.TrimSuffix(fields[0], ":"))
+	if key == "vmflags" {
+		details.Flags = fields[1:]
+		return nil
+	}
+
+	if len(fields) < 2 {
+		return ErrInvalidFileFormat
+	}
+
+	var dst *uint64
+	switch key {
+	case "size":
+		dst = &details.Size
+	case "kernelpagesize":
+		dst = &details.KernelPageSize
+	case "mmupagesize":
+		dst = &details.MMUPageSize
+	case "rss":
+		dst = &details.Resident
+	case "pss":
+		dst = &details.Proportional
+	case "shared_clean":
+		dst = &details.SharedClean
+	case "shared_dirty":
+		dst = &details.SharedDirty
+	case "private_clean":
+		dst = &details.PrivateClean
+	case "private_dirty":
+		dst = &details.PrivateDirty
+	case "referenced":
+		dst = &details.Referenced
+	case "anonymous":
+		dst = &details.Anonymous
+	case "anonhugepages":
+		dst = &details.AnonymousHugePages
+	case "swap":
+		dst = &details.Swap
+	case "swappss":
+		dst = &details.SwapProportional
+	case "locked":
+		dst = &details.Locked
+	default:
+		return nil
+	}
+
+	val, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return err
+	}
+
+	// sizes are converted to bytes, like the size of the mapping itself
+	if len(fields) > 2 && fields[2] == "kB" {
+		val *= 1024
+	}
+	*dst = val
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package sysinfo
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	NamespaceCgroup = "cgroup"
+	NamespaceIPC    = "ipc"
+	NamespaceMount  = "mnt"
+	NamespaceNet    = "net"
+	NamespacePID    = "pid"
+	NamespaceTime   = "time"
+	NamespaceUser   = "user"
+	NamespaceUTS    = "uts"
+)
+
+var namespaceTypes = []string{
+	NamespaceCgroup,
+	NamespaceIPC,
+	NamespaceMount,
+	NamespaceNet,
+	NamespacePID,
+	NamespaceTime,
+	NamespaceUser,
+	NamespaceUTS,
+}
+
+type NamespaceInfo struct {
+	Type       string
+	ID         uint64
+	ProcessIDs []uint64
+}
+
+func (pi *ProcessInfo) SharesNamespace(other *ProcessInfo, nsType string) bool {
+	id, ok := pi.Namespaces[nsType]
+	if !ok || other == nil {
+		return false
+	}
+
+	return id == other.Namespaces[nsType]
+}
+
+func (pi *ProcessInfo) Containerized() (bool, error) {
+	init := &ProcessInfo{}
+	if err := readProcNamespaces(1, init); err != nil {
+		return false, processError(1, err)
+	}
+
+	if !pi.Loaded(ProcessFieldNamespaces) {
+		if err := pi.Load(ProcessFieldNamespaces); err != nil {
+			return false, processError(pi.ID, err)
+		}
+	}
+
+	for _, nsType := range []string{NamespacePID, NamespaceMount} {
+		if _, ok := pi.Namespaces[nsType]; !ok {
+			return false, ErrPermissionDenied
+		}
+		if !pi.SharesNamespace(init, nsType) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func ListNamespaces() ([]*NamespaceInfo, error) {
+	procs, err := ProcessList(WithFields(ProcessFieldNamespaces))
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct {
+		nsType string
+		id     uint64
+	}
+
+	groups := map[key]*NamespaceInfo{}
+	for _, proc := range procs {
+		for nsType, id := range proc.Namespaces {
+			k := key{nsType, id}
+
+			ns, ok := groups[k]
+			if !ok {
+				ns = &NamespaceInfo{Type: nsType, ID: id}
+				groups[k] = ns
+			}
+
+			ns.ProcessIDs = append(ns.ProcessIDs, proc.ID)
+		}
+	}
+
+	namespaces := make([]*NamespaceInfo, 0, len(groups))
+	for _, ns := range groups {
+		namespaces = append(namespaces, ns)
+	}
+
+	sort.Slice(namespaces, func(i, j int) bool {
+		if namespaces[i].Type != namespaces[j].Type {
+			return namespaces[i].Type < namespaces[j].Type
+		}
+
+		return namespaces[i].ID < namespaces[j].ID
+	})
+
+	return namespaces, nil
+}
+
+func readProcNamespaces(pid uint64, proc *ProcessInfo) error {
+	namespaces := map[string]uint64{}
+	for _, nsType := range namespaceTypes {
+		target, err := os.Readlink(fmt.Sprintf(processFile, pid, "ns/"+nsType))
+		if err != nil {
+			// namespace types unsupported by the running kernel are missing
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return err
+		}
+
+		// the link target has the form "type:[inode]"
+		idx := strings.IndexByte(target, '[')
+		if idx == -1 || !strings.HasSuffix(target, "]") {
+			return ErrInvalidFileFormat
+		}
+
+		id, err := strconv.ParseUint(target[idx+1:len(target)-1], 10, 64)
+		if err != nil {
+			return err
+		}
+
+		namespaces[nsType] = id
+	}
+
+	proc.Namespaces = namespaces
+	return nil
+}
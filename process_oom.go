@@ -0,0 +1,107 @@
+package sysinfo
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"syscall"
+)
+
+const (
+	OOMScoreAdjMin = -1000
+	OOMScoreAdjMax = 1000
+)
+
+type ProcessOOMInfo struct {
+	Process  *ProcessInfo
+	Score    int64
+	ScoreAdj int64
+}
+
+func (pi *ProcessInfo) OOMScore() (int64, error) {
+	return readProcIntFile(pi.ID, "oom_score")
+}
+
+func (pi *ProcessInfo) OOMScoreAdj() (int64, error) {
+	return readProcIntFile(pi.ID, "oom_score_adj")
+}
+
+func (pi *ProcessInfo) OOMAdj() (int64, error) {
+	return readProcIntFile(pi.ID, "oom_adj")
+}
+
+func (pi *ProcessInfo) SetOOMScoreAdj(adj int64) error {
+	if adj < OOMScoreAdjMin || adj > OOMScoreAdjMax {
+		return syscall.EINVAL
+	}
+
+	file, err := os.OpenFile(fmt.Sprintf(processFile, pi.ID, "oom_score_adj"), os.O_WRONLY, 0)
+	if err != nil {
+		return controlError(pi.ID, "set OOM score adjustment of", processError(pi.ID, err))
+	}
+	defer file.Close()
+
+	// lowering the adjustment below its previous minimum requires the
+	// CAP_SYS_RESOURCE capability, which is reported when writing
+	if _, err := file.WriteString(strconv.FormatInt(adj, 10)); err != nil {
+		return controlError(pi.ID, "set OOM score adjustment of", processError(pi.ID, err))
+	}
+
+	return nil
+}
+
+func OOMCandidates(opts ...ProcessListOption) ([]*ProcessOOMInfo, error) {
+	procs, err := ProcessList(append([]ProcessListOption{WithFields(0)}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []*ProcessOOMInfo
+	for _, proc := range procs {
+		if proc.KernelThread {
+			continue
+		}
+
+		score, err := proc.OOMScore()
+		if err != nil {
+			continue
+		}
+
+		adj, err := proc.OOMScoreAdj()
+		if err != nil {
+			continue
+		}
+
+		// processes with the minimum adjustment are never killed
+		if adj == OOMScoreAdjMin {
+			continue
+		}
+
+		candidates = append(candidates, &ProcessOOMInfo{
+			Process:  proc,
+			Score:    score,
+			ScoreAdj: adj,
+		})
+	}
+
+	// the kernel kills the process with the highest score first
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+
+		return candidates[i].Process.Memory.Resident > candidates[j].Process.Memory.Resident
+	})
+
+	return candidates, nil
+}
+
+func readProcIntFile(pid uint64, name string) (int64, error) {
+	content, err := readSingleValueFile(fmt.Sprintf(processFile, pid, name))
+	if err != nil {
+		return 0, processError(pid, err)
+	}
+
+	return strconv.ParseInt(content, 10, 64)
+}
@@ -0,0 +1,168 @@
+package sysinfo
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+const (
+	SortByCPU = iota
+	SortByMemory
+	SortByIO
+	SortByFaults
+)
+
+type ProcessSampleInfo struct {
+	Process         *ProcessInfo
+	CPUUsagePercent float64
+	ReadRate        float64
+	WriteRate       float64
+	FaultRate       float64
+}
+
+type ProcessSampler struct {
+	Interval time.Duration
+
+	options  []ProcessListOption
+	previous map[ProcessIdentity]*ProcessInfo
+	scanned  time.Time
+}
+
+func NewProcessSampler(interval time.Duration, opts ...ProcessListOption) *ProcessSampler {
+	options := []ProcessListOption{WithFields(ProcessFieldCmdline)}
+	options = append(options, opts...)
+	options = append(options, func(o *processListOptions) {
+		o.fields |= ProcessFieldIO
+	})
+
+	return &ProcessSampler{
+		Interval: interval,
+		options:  options,
+	}
+}
+
+func (s *ProcessSampler) Sample(ctx context.Context) ([]*ProcessSampleInfo, error) {
+	// the first sample needs a baseline scan, later samples are computed
+	// against the previous one. Either way, at least an interval passes
+	// between the two scans.
+	if s.previous == nil {
+		if _, err := s.scan(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if wait := time.Until(s.scanned.Add(s.Interval)); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+
+	previous := s.previous
+	procs, err := s.scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]*ProcessSampleInfo, 0, len(procs))
+	for _, proc := range procs {
+		samples = append(samples, newProcessSample(previous[proc.Identity()], proc))
+	}
+
+	return samples, nil
+}
+
+func (s *ProcessSampler) Top(ctx context.Context, n int, sortBy int) ([]*ProcessSampleInfo, error) {
+	samples, err := s.Sample(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return TopProcessSamples(samples, n, sortBy), nil
+}
+
+func (s *ProcessSampler) scan(ctx context.Context) ([]*ProcessInfo, error) {
+	scan, err := ScanProcesses(ctx, s.options...)
+	if err != nil {
+		return nil, err
+	}
+
+	s.scanned = time.Now()
+	s.previous = make(map[ProcessIdentity]*ProcessInfo, len(scan.Processes))
+	for _, proc := range scan.Processes {
+		s.previous[proc.Identity()] = proc
+	}
+
+	return scan.Processes, nil
+}
+
+func TopProcessSamples(samples []*ProcessSampleInfo, n int, sortBy int) []*ProcessSampleInfo {
+	sorted := make([]*ProcessSampleInfo, len(samples))
+	copy(sorted, samples)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].value(sortBy) > sorted[j].value(sortBy)
+	})
+
+	if n >= 0 && n < len(sorted) {
+		sorted = sorted[:n]
+	}
+
+	return sorted
+}
+
+func (si *ProcessSampleInfo) value(sortBy int) float64 {
+	switch sortBy {
+	case SortByMemory:
+		return float64(si.Process.Memory.Resident)
+	case SortByIO:
+		return si.ReadRate + si.WriteRate
+	case SortByFaults:
+		return si.FaultRate
+	}
+
+	return si.CPUUsagePercent
+}
+
+func newProcessSample(prev, curr *ProcessInfo) *ProcessSampleInfo {
+	sample := &ProcessSampleInfo{Process: curr}
+
+	// processes started after the previous scan are measured over their
+	// whole lifetime
+	if prev == nil {
+		elapsed := curr.CPU.sysUptime - float64(curr.CPU.Start)/float64(TicksPerSecond)
+		if elapsed <= 0 {
+			return sample
+		}
+
+		sample.CPUUsagePercent = 100 * float64(ownCPUTicks(curr.CPU)) / float64(TicksPerSecond) / elapsed
+		if curr.IO != nil {
+			sample.ReadRate = float64(curr.IO.ReadBytes) / elapsed
+			sample.WriteRate = float64(curr.IO.WriteBytes) / elapsed
+		}
+		sample.FaultRate = float64(curr.MinorFaults+curr.MajorFaults) / elapsed
+		return sample
+	}
+
+	sample.ReadRate, sample.WriteRate = ProcessIORate(prev.IO, curr.IO)
+
+	if elapsed := curr.CPU.sysUptime - prev.CPU.sysUptime; elapsed > 0 {
+		ticks := ownCPUTicks(curr.CPU) - ownCPUTicks(prev.CPU)
+		sample.CPUUsagePercent = 100 * float64(ticks) / float64(TicksPerSecond) / elapsed
+
+		faults := (curr.MinorFaults + curr.MajorFaults) - (prev.MinorFaults + prev.MajorFaults)
+		sample.FaultRate = float64(faults) / elapsed
+	}
+
+	return sample
+}
+
+// the time of reaped children is added to the parent when they exit, which
+// would show up as a spike in the usage of the parent
+func ownCPUTicks(cpu *ProcessCPUInfo) uint64 {
+	return cpu.User + cpu.System + cpu.Guest
+}
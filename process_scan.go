@@ -0,0 +1,126 @@
+package sysinfo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+type ProcessScanInfo struct {
+	Processes []*ProcessInfo
+	Errors    []*ProcessScanError
+}
+
+type ProcessScanError struct {
+	ID  uint64
+	Err error
+}
+
+func (e *ProcessScanError) Error() string {
+	return fmt.Sprintf("process %d: %v", e.ID, e.Err)
+}
+
+func (e *ProcessScanError) Unwrap() error {
+	return e.Err
+}
+
+func ScanProcesses(ctx context.Context, opts ...ProcessListOption) (*ProcessScanInfo, error) {
+	pids, err := readProcessIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	uptime, err := Uptime()
+	if err != nil {
+		return nil, err
+	}
+
+	options := newProcessListOptions(opts)
+
+	// each worker writes only to the slots of the PIDs it picks up, so the
+	// results keep the PID order without further synchronization
+	procs := make([]*ProcessInfo, len(pids))
+	errs := make([]error, len(pids))
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < options.concurrency && i < len(pids); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range indices {
+				procs[idx], errs[idx] = readProcess(pids[idx], options, uptime)
+			}
+		}()
+	}
+
+feed:
+	for idx := range pids {
+		select {
+		case indices <- idx:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indices)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	scan := &ProcessScanInfo{}
+	for idx, proc := range procs {
+		if err := errs[idx]; err != nil {
+			// processes exiting during the scan are not failures
+			if err = processError(pids[idx], err); err != ErrProcessNotFound {
+				scan.Errors = append(scan.Errors, &ProcessScanError{ID: pids[idx], Err: err})
+			}
+
+			continue
+		}
+		if proc == nil {
+			continue
+		}
+
+		scan.Processes = append(scan.Processes, proc)
+	}
+
+	return scan, nil
+}
+
+func readProcessIDs() ([]uint64, error) {
+	dir, err := os.Open("/proc")
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+
+	names, err := dir.Readdirnames(-1)
+	if err != nil {
+		return nil, err
+	}
+
+	pids := make([]uint64, 0, len(names))
+	for _, name := range names {
+		if name[0] < '0' || name[0] > '9' {
+			continue
+		}
+
+		pid, err := strconv.ParseUint(name, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		pids = append(pids, pid)
+	}
+
+	sort.Slice(pids, func(i, j int) bool {
+		return pids[i] < pids[j]
+	})
+
+	return pids, nil
+}
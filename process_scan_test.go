@@ -0,0 +1,47 @@
+package sysinfo
+
+import (
+	"context"
+	"testing"
+)
+
+func benchmarkScanProcesses(b *testing.B, opts ...ProcessListOption) {
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := ScanProcesses(ctx, opts...); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkScanProcessesSequential(b *testing.B) {
+	benchmarkScanProcesses(b, WithConcurrency(1))
+}
+
+func BenchmarkScanProcessesParallel(b *testing.B) {
+	benchmarkScanProcesses(b)
+}
+
+func BenchmarkScanProcessesFields(b *testing.B) {
+	masks := []struct {
+		name   string
+		fields ProcessField
+	}{
+		{"None", 0},
+		{"Cmdline", ProcessFieldCmdline},
+		{"IO", ProcessFieldIO},
+		{"FDs", ProcessFieldFDs},
+		{"Namespaces", ProcessFieldNamespaces},
+		{"Cgroups", ProcessFieldCgroups},
+		{"Scheduler", ProcessFieldScheduler},
+		{"All", ProcessFieldAll},
+	}
+
+	for _, mask := range masks {
+		b.Run(mask.name, func(b *testing.B) {
+			benchmarkScanProcesses(b, WithFields(mask.fields))
+		})
+	}
+}
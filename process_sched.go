@@ -0,0 +1,46 @@
+package sysinfo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ProcessSchedulerInfo struct {
+	VoluntaryContextSwitches    uint64
+	NonvoluntaryContextSwitches uint64
+	RunTime                     time.Duration
+	WaitTime                    time.Duration
+	Timeslices                  uint64
+}
+
+func readProcSchedstatFile(pid uint64, proc *ProcessInfo) error {
+	content, err := readSingleValueFile(fmt.Sprintf(processFile, pid, "schedstat"))
+	if err != nil {
+		return err
+	}
+
+	fields := strings.Fields(content)
+	if len(fields) != 3 {
+		return ErrInvalidFileFormat
+	}
+
+	runTime, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return err
+	}
+
+	waitTime, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return err
+	}
+
+	proc.Scheduler.RunTime = time.Duration(runTime)
+	proc.Scheduler.WaitTime = time.Duration(waitTime)
+	if proc.Scheduler.Timeslices, err = strconv.ParseUint(fields[2], 10, 64); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package sysinfo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitProcStat(t *testing.T) {
+	tests := []struct {
+		content string
+		comm    string
+		fields  []string
+		err     error
+	}{
+		{"1 (systemd) S 0 1 1", "systemd", []string{"S", "0", "1", "1"}, nil},
+		{"42 (tmux: server) S 1 42", "tmux: server", []string{"S", "1", "42"}, nil},
+		{"43 (Web Content) R 42 43", "Web Content", []string{"R", "42", "43"}, nil},
+		{"44 (a) b)) S 1 44", "a) b)", []string{"S", "1", "44"}, nil},
+		{"45 (() Z 1 45", "(", []string{"Z", "1", "45"}, nil},
+		{"46 () S 1", "", []string{"S", "1"}, nil},
+		{"47 sleep S 1", "", nil, ErrInvalidFileFormat},
+		{"48 )sleep( S 1", "", nil, ErrInvalidFileFormat},
+	}
+
+	for _, test := range tests {
+		comm, fields, err := splitProcStat(test.content)
+		if err != test.err {
+			t.Errorf("%q: expected error %v, got %v", test.content, test.err, err)
+			continue
+		}
+		if comm != test.comm {
+			t.Errorf("%q: expected name %q, got %q", test.content, test.comm, comm)
+		}
+		if !reflect.DeepEqual(fields, test.fields) {
+			t.Errorf("%q: expected fields %q, got %q", test.content, test.fields, fields)
+		}
+	}
+}
+
+func TestParseProcCmdline(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		exists      bool
+		path        string
+		args        []string
+		commandLine string
+		rewritten   bool
+	}{
+		{"empty", "", false, "", nil, "", false},
+		{"single", "/bin/sleep\x00", false, "/bin/sleep", []string{}, "/bin/sleep", false},
+		{"args", "sleep\x0010\x00", false, "sleep", []string{"10"}, "sleep 10", false},
+		{"empty args", "printf\x00\x00\x00x\x00", false, "printf", []string{"", "", "x"}, "printf   x", false},
+		{"args with spaces", "sh\x00-c\x00echo a  b\x00", false, "sh", []string{"-c", "echo a  b"}, "sh -c echo a  b", false},
+		{"no trailing nul", "sleep\x0010", false, "sleep", []string{"10"}, "sleep 10", false},
+		{"set title", "nginx: worker process\x00\x00\x00\x00", false, "", nil, "nginx: worker process", true},
+		{"set title padded", "postgres: checkpointer   \x00", false, "", nil, "postgres: checkpointer", true},
+		{"path with spaces", "/opt/my app/bin\x00", true, "/opt/my app/bin", []string{}, "/opt/my app/bin", false},
+		{"whitespace only", " \x00", false, " ", []string{}, " ", false},
+		{"spaces only", "   ", false, "   ", []string{}, "   ", false},
+	}
+
+	for _, test := range tests {
+		proc := &ProcessInfo{Path: "stale", Arguments: []string{"stale"}}
+		parseProcCmdline(test.content, proc, func(string) bool { return test.exists })
+
+		if proc.Path != test.path {
+			t.Errorf("%s: expected path %q, got %q", test.name, test.path, proc.Path)
+		}
+		if !reflect.DeepEqual(proc.Arguments, test.args) {
+			t.Errorf("%s: expected arguments %q, got %q", test.name, test.args, proc.Arguments)
+		}
+		if proc.CommandLine != test.commandLine {
+			t.Errorf("%s: expected command line %q, got %q", test.name, test.commandLine, proc.CommandLine)
+		}
+		if proc.TitleRewritten != test.rewritten {
+			t.Errorf("%s: expected rewritten title %v, got %v", test.name, test.rewritten, proc.TitleRewritten)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package sysinfo
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var threadFile = "/proc/%d/task/%d/%s"
+
+type ProcessThreadInfo struct {
+	ID                          uint64
+	Name                        string
+	State                       string
+	LastCPU                     uint64
+	VoluntaryContextSwitches    uint64
+	NonvoluntaryContextSwitches uint64
+	AllowedCPUs                 CPUSet
+
+	CPU *ProcessCPUInfo
+}
+
+func (ti *ProcessThreadInfo) CPUUsagePercent() float64 {
+	elapsed := ti.CPU.sysUptime - float64(ti.CPU.Start)/float64(TicksPerSecond)
+	if elapsed == 0 {
+		return 0.0
+	}
+
+	return 100 * float64(ti.CPU.Total) / float64(TicksPerSecond) / elapsed
+}
+
+func (pi *ProcessInfo) Threads() ([]*ProcessThreadInfo, error) {
+	dir, err := os.Open(fmt.Sprintf(processFile, pi.ID, "task"))
+	if err != nil {
+		return nil, processError(pi.ID, err)
+	}
+	names, err := dir.Readdirnames(-1)
+	dir.Close()
+	if err != nil {
+		return nil, processError(pi.ID, err)
+	}
+
+	uptime, err := Uptime()
+	if err != nil {
+		return nil, err
+	}
+
+	var threads []*ProcessThreadInfo
+	for _, name := range names {
+		tid, err := strconv.ParseUint(name, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		thread := &ProcessThreadInfo{
+			ID:  tid,
+			CPU: &ProcessCPUInfo{sysUptime: uptime},
+		}
+
+		if err := readThreadFiles(pi.ID, thread); err != nil {
+			// the thread exited while it was being read
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return nil, processError(pi.ID, err)
+		}
+
+		threads = append(threads, thread)
+	}
+
+	return threads, nil
+}
+
+func readThreadFiles(pid uint64, thread *ProcessThreadInfo) error {
+	comm, err := readSingleValueFile(fmt.Sprintf(threadFile, pid, thread.ID, "comm"))
+	if err != nil {
+		return err
+	}
+	thread.Name = comm
+
+	if err := readThreadStatFile(pid, thread); err != nil {
+		return err
+	}
+	if err := readThreadStatusFile(pid, thread); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func readThreadStatFile(pid uint64, thread *ProcessThreadInfo) error {
+	content, err := readSingleValueFile(fmt.Sprintf(threadFile, pid, thread.ID, "stat"))
+	if err != nil {
+		return err
+	}
+
+	_, fields, err := splitProcStat(content)
+	if err != nil {
+		return err
+	}
+	if len(fields) < 42 {
+		return ErrInvalidFileFormat
+	}
+
+	cpu := thread.CPU
+	if cpu.User, err = strconv.ParseUint(fields[11], 10, 64); err != nil {
+		return err
+	}
+
+	if cpu.System, err = strconv.ParseUint(fields[12], 10, 64); err != nil {
+		return err
+	}
+
+	if cpu.Start, err = strconv.ParseUint(fields[19], 10, 64); err != nil {
+		return err
+	}
+
+	if thread.LastCPU, err = strconv.ParseUint(fields[36], 10, 64); err != nil {
+		return err
+	}
+
+	if cpu.Guest, err = strconv.ParseUint(fields[40], 10, 64); err != nil {
+		return err
+	}
+
+	cpu.User -= cpu.Guest
+	cpu.Total = cpu.User + cpu.System + cpu.Guest
+
+	return nil
+}
+
+func readThreadStatusFile(pid uint64, thread *ProcessThreadInfo) error {
+	file, err := os.Open(fmt.Sprintf(threadFile, pid, thread.ID, "status"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		key := strings.ToLower(strings.TrimSuffix(fields[0], ":"))
+		switch key {
+		case "state":
+			thread.State = fields[1]
+
+		case "voluntary_ctxt_switches":
+			thread.VoluntaryContextSwitches, err = strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return err
+			}
+
+		case "nonvoluntary_ctxt_switches":
+			thread.NonvoluntaryContextSwitches, err = strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return err
+			}
+
+		case "cpus_allowed_list":
+			thread.AllowedCPUs, err = ParseCPUSet(fields[1])
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+
+	return nil
+}
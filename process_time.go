@@ -0,0 +1,64 @@
+package sysinfo
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	bootTime     time.Time
+	bootTimeErr  error
+	bootTimeOnce sync.Once
+)
+
+type ProcessIdentity struct {
+	ID    uint64
+	Start uint64
+}
+
+func (pi *ProcessInfo) Identity() ProcessIdentity {
+	return ProcessIdentity{ID: pi.ID, Start: pi.CPU.Start}
+}
+
+func (pi *ProcessInfo) SameProcess(other *ProcessInfo) bool {
+	return other != nil && pi.Identity() == other.Identity()
+}
+
+func (pi *ProcessInfo) StartTime() (time.Time, error) {
+	boot, err := cachedBootTime()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return boot.Add(ticksToDuration(pi.CPU.Start)), nil
+}
+
+func (pi *ProcessInfo) Age() (time.Duration, error) {
+	uptime, err := Uptime()
+	if err != nil {
+		return 0, err
+	}
+
+	age := time.Duration(uptime*float64(time.Second)) - ticksToDuration(pi.CPU.Start)
+	if age < 0 {
+		return 0, nil
+	}
+
+	return age, nil
+}
+
+func cachedBootTime() (time.Time, error) {
+	bootTimeOnce.Do(func() {
+		bootTime, bootTimeErr = BootTime()
+	})
+
+	return bootTime, bootTimeErr
+}
+
+func ticksToDuration(ticks uint64) time.Duration {
+	// whole seconds are converted separately, multiplying the ticks by a
+	// second in nanoseconds overflows after a few years of uptime
+	tps := TicksPerSecond
+	return time.Duration(ticks/tps)*time.Second +
+		time.Duration(ticks%tps)*time.Second/time.Duration(tps)
+}
@@ -0,0 +1,161 @@
+package sysinfo
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+)
+
+type ProcessTreeInfo struct {
+	Roots []*ProcessTreeNode
+
+	nodes map[uint64]*ProcessTreeNode
+}
+
+type ProcessTreeNode struct {
+	Process *ProcessInfo
+	Parent  *ProcessTreeNode
+
+	children []*ProcessTreeNode
+}
+
+func (t *ProcessTreeInfo) Node(pid uint64) *ProcessTreeNode {
+	return t.nodes[pid]
+}
+
+func (t *ProcessTreeInfo) Render(w io.Writer) error {
+	for _, root := range t.Roots {
+		if err := root.Render(w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *ProcessTreeInfo) String() string {
+	var buf bytes.Buffer
+	t.Render(&buf)
+
+	return buf.String()
+}
+
+func (n *ProcessTreeNode) Children() []*ProcessTreeNode {
+	return n.children
+}
+
+func (n *ProcessTreeNode) Descendants() []*ProcessTreeNode {
+	var descendants []*ProcessTreeNode
+	for _, child := range n.children {
+		descendants = append(descendants, child)
+		descendants = append(descendants, child.Descendants()...)
+	}
+
+	return descendants
+}
+
+func (n *ProcessTreeNode) Ancestors() []*ProcessTreeNode {
+	var ancestors []*ProcessTreeNode
+	for p := n.Parent; p != nil; p = p.Parent {
+		ancestors = append(ancestors, p)
+	}
+
+	return ancestors
+}
+
+func (n *ProcessTreeNode) TotalCPU() uint64 {
+	total := n.Process.CPU.Total
+	for _, child := range n.children {
+		total += child.TotalCPU()
+	}
+
+	return total
+}
+
+func (n *ProcessTreeNode) TotalCPUUsagePercent() float64 {
+	total := n.Process.CPUUsagePercent()
+	for _, child := range n.children {
+		total += child.TotalCPUUsagePercent()
+	}
+
+	return total
+}
+
+func (n *ProcessTreeNode) TotalMemory() uint64 {
+	total := n.Process.Memory.Resident
+	for _, child := range n.children {
+		total += child.TotalMemory()
+	}
+
+	return total
+}
+
+func (n *ProcessTreeNode) Render(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "%d %s\n", n.Process.ID, n.Process.Name); err != nil {
+		return err
+	}
+
+	return n.renderChildren(w, "")
+}
+
+func (n *ProcessTreeNode) renderChildren(w io.Writer, prefix string) error {
+	for i, child := range n.children {
+		branch, indent := "├─ ", "│  "
+		if i == len(n.children)-1 {
+			branch, indent = "└─ ", "   "
+		}
+
+		_, err := fmt.Fprintf(w, "%s%s%d %s\n", prefix, branch, child.Process.ID, child.Process.Name)
+		if err != nil {
+			return err
+		}
+
+		if err := child.renderChildren(w, prefix+indent); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func ProcessTree() (*ProcessTreeInfo, error) {
+	procs, err := ProcessList(WithFields(0))
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildProcessTree(procs), nil
+}
+
+func BuildProcessTree(procs []*ProcessInfo) *ProcessTreeInfo {
+	tree := &ProcessTreeInfo{nodes: map[uint64]*ProcessTreeNode{}}
+	for _, proc := range procs {
+		tree.nodes[proc.ID] = &ProcessTreeNode{Process: proc}
+	}
+
+	for _, node := range tree.nodes {
+		// processes whose parent is missing from the snapshot are roots
+		parent, ok := tree.nodes[node.Process.ParentID]
+		if !ok || parent == node {
+			tree.Roots = append(tree.Roots, node)
+			continue
+		}
+
+		node.Parent = parent
+		parent.children = append(parent.children, node)
+	}
+
+	sortProcessTreeNodes(tree.Roots)
+	for _, node := range tree.nodes {
+		sortProcessTreeNodes(node.children)
+	}
+
+	return tree
+}
+
+func sortProcessTreeNodes(nodes []*ProcessTreeNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Process.ID < nodes[j].Process.ID
+	})
+}
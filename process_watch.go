@@ -0,0 +1,334 @@
+package sysinfo
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"syscall"
+	"time"
+)
+
+const (
+	ProcessEventStart = iota
+	ProcessEventExit
+	ProcessEventExec
+)
+
+const (
+	netlinkConnector = 11
+
+	cnIdxProc = 1
+	cnValProc = 1
+
+	procCnMcastListen = 1
+
+	procEventNone = 0x00000000
+	procEventFork = 0x00000001
+	procEventExec = 0x00000002
+	procEventExit = 0x80000000
+
+	cnMsgLen        = 20
+	procEventHdrLen = 16
+)
+
+type ProcessEvent struct {
+	Type    int
+	Process *ProcessInfo
+	Time    time.Time
+}
+
+func WatchProcesses(ctx context.Context, interval time.Duration) (<-chan *ProcessEvent, error) {
+	// the interval paces the polling and bounds the connector receive
+	// timeout, so it has to be positive in both modes
+	if interval <= 0 {
+		return nil, ErrInvalidInterval
+	}
+
+	procs, err := ProcessList(WithFields(ProcessFieldCmdline))
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan *ProcessEvent, 64)
+
+	// the proc connector reports every event as it happens, but it is only
+	// available to privileged processes in the initial network namespace
+	if fd, err := openProcConnector(); err == nil {
+		go watchProcConnector(ctx, fd, interval, procs, events)
+		return events, nil
+	}
+
+	go watchProcessScans(ctx, interval, procs, events)
+	return events, nil
+}
+
+func watchProcessScans(ctx context.Context, interval time.Duration,
+	procs []*ProcessInfo, events chan<- *ProcessEvent) {
+	defer close(events)
+
+	known := map[ProcessIdentity]*ProcessInfo{}
+	for _, proc := range procs {
+		known[proc.Identity()] = proc
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		procs, err := ProcessList(WithFields(ProcessFieldCmdline))
+		if err != nil {
+			continue
+		}
+
+		now := time.Now()
+		current := make(map[ProcessIdentity]*ProcessInfo, len(procs))
+		for _, proc := range procs {
+			id := proc.Identity()
+			current[id] = proc
+
+			prev, ok := known[id]
+			switch {
+			case !ok:
+				if !sendProcessEvent(ctx, events, ProcessEventStart, proc, now) {
+					return
+				}
+			case !proc.KernelThread && prev.CommandLine != proc.CommandLine:
+				if !sendProcessEvent(ctx, events, ProcessEventExec, proc, now) {
+					return
+				}
+			}
+		}
+
+		for id, proc := range known {
+			if _, ok := current[id]; ok {
+				continue
+			}
+			if !sendProcessEvent(ctx, events, ProcessEventExit, proc, now) {
+				return
+			}
+		}
+
+		known = current
+	}
+}
+
+func watchProcConnector(ctx context.Context, fd int, interval time.Duration,
+	procs []*ProcessInfo, events chan<- *ProcessEvent) {
+	defer close(events)
+	defer syscall.Close(fd)
+
+	known := map[uint64]*ProcessInfo{}
+	for _, proc := range procs {
+		known[proc.ID] = proc
+	}
+
+	// the receive timeout bounds how long cancellation goes unnoticed
+	tv := syscall.NsecToTimeval(interval.Nanoseconds())
+	syscall.SetsockoptTimeval(fd, syscall.SOL_SOCKET, syscall.SO_RCVTIMEO, &tv)
+
+	order := nativeByteOrder()
+	buf := make([]byte, os.Getpagesize())
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		n, _, err := syscall.Recvfrom(fd, buf, 0)
+		if err != nil {
+			// timeouts and receive buffer overruns are not fatal
+			if err == syscall.EAGAIN || err == syscall.EINTR || err == syscall.ENOBUFS {
+				continue
+			}
+
+			return
+		}
+
+		msgs, err := syscall.ParseNetlinkMessage(buf[:n])
+		if err != nil {
+			continue
+		}
+
+		for _, msg := range msgs {
+			data := msg.Data
+			if len(data) < cnMsgLen+procEventHdrLen+8 {
+				continue
+			}
+
+			event := data[cnMsgLen:]
+			what := order.Uint32(event[0:4])
+			body := event[procEventHdrLen:]
+			now := time.Now()
+
+			var pid, tgid uint64
+			var eventType int
+			switch what {
+			case procEventFork:
+				if len(body) < 16 {
+					continue
+				}
+
+				pid, tgid = uint64(order.Uint32(body[8:12])), uint64(order.Uint32(body[12:16]))
+				eventType = ProcessEventStart
+			case procEventExec:
+				// a thread calling exec takes over the ID of the process
+				tgid = uint64(order.Uint32(body[4:8]))
+				pid, eventType = tgid, ProcessEventExec
+			case procEventExit:
+				pid, tgid = uint64(order.Uint32(body[0:4])), uint64(order.Uint32(body[4:8]))
+				eventType = ProcessEventExit
+			default:
+				continue
+			}
+
+			// thread creation and termination are not process events
+			if pid != tgid {
+				continue
+			}
+
+			proc := known[pid]
+			if eventType == ProcessEventExit {
+				delete(known, pid)
+
+				// the last known information might have been sent already,
+				// so the exit code is set on a copy
+				exited := &ProcessInfo{ID: pid}
+				if proc != nil {
+					*exited = *proc
+				}
+				if len(body) >= 12 {
+					exited.ExitCode = int64(int32(order.Uint32(body[8:12])))
+				}
+
+				proc = exited
+			} else {
+				if p, err := readProcessFields(pid, ProcessFieldCmdline); err == nil {
+					proc = p
+				} else if proc == nil {
+					proc = &ProcessInfo{ID: pid}
+				}
+				known[pid] = proc
+			}
+
+			if !sendProcessEvent(ctx, events, eventType, proc, now) {
+				return
+			}
+		}
+	}
+}
+
+func openProcConnector() (int, error) {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC, netlinkConnector)
+	if err != nil {
+		return -1, err
+	}
+
+	addr := &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK, Groups: cnIdxProc}
+	if err := syscall.Bind(fd, addr); err != nil {
+		syscall.Close(fd)
+		return -1, err
+	}
+
+	// nlmsghdr, cn_msg and the listen operation
+	order := nativeByteOrder()
+	msg := make([]byte, syscall.NLMSG_HDRLEN+cnMsgLen+4)
+	order.PutUint32(msg[0:4], uint32(len(msg)))
+	order.PutUint16(msg[4:6], syscall.NLMSG_DONE)
+	order.PutUint32(msg[12:16], uint32(os.Getpid()))
+
+	ack := uint32(time.Now().UnixNano())
+	cn := msg[syscall.NLMSG_HDRLEN:]
+	order.PutUint32(cn[0:4], cnIdxProc)
+	order.PutUint32(cn[4:8], cnValProc)
+	order.PutUint32(cn[12:16], ack)
+	order.PutUint16(cn[16:18], 4)
+	order.PutUint32(cn[cnMsgLen:], procCnMcastListen)
+
+	if err := syscall.Sendto(fd, msg, 0, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		syscall.Close(fd)
+		return -1, err
+	}
+
+	// the kernel silently ignores subscriptions from outside the initial
+	// PID and user namespaces, so the subscription is only assumed to work
+	// once it has been acknowledged
+	if err := waitProcConnectorAck(fd, ack+1); err != nil {
+		syscall.Close(fd)
+		return -1, err
+	}
+
+	return fd, nil
+}
+
+func waitProcConnectorAck(fd int, ack uint32) error {
+	tv := syscall.NsecToTimeval(int64(100 * time.Millisecond))
+	if err := syscall.SetsockoptTimeval(fd, syscall.SOL_SOCKET, syscall.SO_RCVTIMEO, &tv); err != nil {
+		return err
+	}
+
+	order := nativeByteOrder()
+	buf := make([]byte, os.Getpagesize())
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); {
+		n, _, err := syscall.Recvfrom(fd, buf, 0)
+		if err != nil {
+			if err == syscall.EAGAIN || err == syscall.EINTR || err == syscall.ENOBUFS {
+				continue
+			}
+
+			return err
+		}
+
+		msgs, err := syscall.ParseNetlinkMessage(buf[:n])
+		if err != nil {
+			continue
+		}
+
+		// events of other subscribers might arrive before the
+		// acknowledgement, which carries the incremented ack of the request
+		for _, msg := range msgs {
+			data := msg.Data
+			if len(data) < cnMsgLen+procEventHdrLen+4 || order.Uint32(data[12:16]) != ack {
+				continue
+			}
+
+			event := data[cnMsgLen:]
+			if order.Uint32(event[0:4]) != procEventNone {
+				continue
+			}
+			if errno := order.Uint32(event[procEventHdrLen:]); errno != 0 {
+				return syscall.Errno(errno)
+			}
+
+			return nil
+		}
+	}
+
+	return syscall.ETIMEDOUT
+}
+
+func sendProcessEvent(ctx context.Context, events chan<- *ProcessEvent,
+	eventType int, proc *ProcessInfo, t time.Time) bool {
+	select {
+	case events <- &ProcessEvent{Type: eventType, Process: proc, Time: t}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func readProcessFields(pid uint64, fields ProcessField) (*ProcessInfo, error) {
+	return Process(pid, WithFields(fields))
+}
+
+func nativeByteOrder() binary.ByteOrder {
+	if littleEndian {
+		return binary.LittleEndian
+	}
+
+	return binary.BigEndian
+}
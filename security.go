@@ -0,0 +1,159 @@
+package sysinfo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+)
+
+const securityModulesPath = "/sys/kernel/security/lsm"
+
+const (
+	SecurityModuleSELinux  = "selinux"
+	SecurityModuleAppArmor = "apparmor"
+	SecurityModuleSmack    = "smack"
+)
+
+const (
+	AppArmorModeEnforce    = "enforce"
+	AppArmorModeComplain   = "complain"
+	AppArmorModeKill       = "kill"
+	AppArmorModeUnconfined = "unconfined"
+)
+
+type ProcessSecurityInfo struct {
+	Label           string
+	SELinuxContext  string
+	SELinuxUser     string
+	SELinuxRole     string
+	SELinuxType     string
+	SELinuxLevel    string
+	AppArmorProfile string
+	AppArmorMode    string
+}
+
+func (si *ProcessSecurityInfo) Confined() bool {
+	if si.SELinuxType != "" && !strings.Contains(si.SELinuxType, "unconfined") {
+		return true
+	}
+
+	return si.AppArmorProfile != "" && si.AppArmorProfile != AppArmorModeUnconfined &&
+		si.AppArmorMode != AppArmorModeUnconfined
+}
+
+func SecurityModules() ([]string, error) {
+	content, err := readSingleValueFile(securityModulesPath)
+	if err != nil {
+		return nil, err
+	}
+	if content == "" {
+		return nil, nil
+	}
+
+	return strings.Split(content, ","), nil
+}
+
+func (pi *ProcessInfo) SecurityContext() (*ProcessSecurityInfo, error) {
+	info := &ProcessSecurityInfo{}
+
+	label, err := readProcAttrFile(pi.ID, "current")
+	if err != nil {
+		return nil, processError(pi.ID, err)
+	}
+	info.Label = label
+
+	// with stacked modules AppArmor exposes its own attribute file, otherwise
+	// the shared one belongs to the first major module
+	profile, err := readProcAttrFile(pi.ID, "apparmor/current")
+	if err != nil && !os.IsNotExist(err) {
+		return nil, processError(pi.ID, err)
+	}
+
+	switch {
+	case profile != "":
+		parseAppArmorLabel(info, profile)
+		if isSELinuxContext(label) {
+			parseSELinuxLabel(info, label)
+		}
+	case isSELinuxContext(label):
+		parseSELinuxLabel(info, label)
+	case isAppArmorLabel(label):
+		parseAppArmorLabel(info, label)
+	}
+
+	return info, nil
+}
+
+func UnconfinedProcesses(opts ...ProcessListOption) ([]*ProcessInfo, error) {
+	procs, err := ProcessList(append([]ProcessListOption{WithFields(0)}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var unconfined []*ProcessInfo
+	for _, proc := range procs {
+		if proc.KernelThread {
+			continue
+		}
+
+		info, err := proc.SecurityContext()
+		if err != nil {
+			continue
+		}
+
+		if !info.Confined() {
+			unconfined = append(unconfined, proc)
+		}
+	}
+
+	return unconfined, nil
+}
+
+func readProcAttrFile(pid uint64, name string) (string, error) {
+	content, err := readSingleValueFile(fmt.Sprintf(processFile, pid, "attr/"+name))
+	if err != nil {
+		// the attribute cannot be read when no module provides it
+		if errors.Is(err, syscall.EINVAL) {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	return strings.TrimRight(content, "\x00"), nil
+}
+
+func isSELinuxContext(label string) bool {
+	return len(strings.SplitN(label, ":", 4)) >= 3 && !strings.Contains(label, " ")
+}
+
+func isAppArmorLabel(label string) bool {
+	return label == AppArmorModeUnconfined ||
+		(strings.HasSuffix(label, ")") && strings.Contains(label, " ("))
+}
+
+func parseSELinuxLabel(info *ProcessSecurityInfo, label string) {
+	parts := strings.SplitN(label, ":", 4)
+
+	info.SELinuxContext = label
+	info.SELinuxUser, info.SELinuxRole, info.SELinuxType = parts[0], parts[1], parts[2]
+	if len(parts) > 3 {
+		info.SELinuxLevel = parts[3]
+	}
+}
+
+func parseAppArmorLabel(info *ProcessSecurityInfo, label string) {
+	// confined processes are labeled as "profile (mode)"
+	if i := strings.LastIndex(label, " ("); i >= 0 && strings.HasSuffix(label, ")") {
+		info.AppArmorProfile = label[:i]
+		info.AppArmorMode = label[i+2 : len(label)-1]
+		return
+	}
+
+	info.AppArmorProfile = label
+	if label == AppArmorModeUnconfined {
+		info.AppArmorMode = AppArmorModeUnconfined
+	}
+}
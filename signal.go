@@ -0,0 +1,97 @@
+package sysinfo
+
+import (
+	"strconv"
+	"syscall"
+)
+
+var signalNames = []string{
+	1:  "SIGHUP",
+	2:  "SIGINT",
+	3:  "SIGQUIT",
+	4:  "SIGILL",
+	5:  "SIGTRAP",
+	6:  "SIGABRT",
+	7:  "SIGBUS",
+	8:  "SIGFPE",
+	9:  "SIGKILL",
+	10: "SIGUSR1",
+	11: "SIGSEGV",
+	12: "SIGUSR2",
+	13: "SIGPIPE",
+	14: "SIGALRM",
+	15: "SIGTERM",
+	16: "SIGSTKFLT",
+	17: "SIGCHLD",
+	18: "SIGCONT",
+	19: "SIGSTOP",
+	20: "SIGTSTP",
+	21: "SIGTTIN",
+	22: "SIGTTOU",
+	23: "SIGURG",
+	24: "SIGXCPU",
+	25: "SIGXFSZ",
+	26: "SIGVTALRM",
+	27: "SIGPROF",
+	28: "SIGWINCH",
+	29: "SIGIO",
+	30: "SIGPWR",
+	31: "SIGSYS",
+}
+
+// signalRTMin is the first real-time signal available to applications.
+// Signals 32 and 33 are reserved by the C library.
+const signalRTMin = 34
+
+type SignalSet uint64
+
+type ProcessSignalInfo struct {
+	Queued        uint64
+	QueueLimit    uint64
+	Pending       SignalSet
+	SharedPending SignalSet
+	Blocked       SignalSet
+	Ignored       SignalSet
+	Caught        SignalSet
+}
+
+func (ss SignalSet) Has(sig syscall.Signal) bool {
+	if sig < 1 || sig > 64 {
+		return false
+	}
+
+	return ss&(1<<uint(sig-1)) != 0
+}
+
+func (ss SignalSet) Signals() []syscall.Signal {
+	var signals []syscall.Signal
+	for sig := syscall.Signal(1); sig <= 64; sig++ {
+		if ss.Has(sig) {
+			signals = append(signals, sig)
+		}
+	}
+
+	return signals
+}
+
+func (ss SignalSet) Names() []string {
+	var names []string
+	for _, sig := range ss.Signals() {
+		names = append(names, SignalName(sig))
+	}
+
+	return names
+}
+
+func SignalName(sig syscall.Signal) string {
+	switch {
+	case sig > 0 && int(sig) < len(signalNames):
+		return signalNames[sig]
+	case sig == signalRTMin:
+		return "SIGRTMIN"
+	case sig > signalRTMin && sig <= 64:
+		return "SIGRTMIN+" + strconv.Itoa(int(sig-signalRTMin))
+	}
+
+	return "SIG" + strconv.Itoa(int(sig))
+}
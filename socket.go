@@ -0,0 +1,352 @@
+package sysinfo
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+const (
+	SocketProtocolTCP  = "tcp"
+	SocketProtocolTCP6 = "tcp6"
+	SocketProtocolUDP  = "udp"
+	SocketProtocolUDP6 = "udp6"
+	SocketProtocolRaw  = "raw"
+	SocketProtocolRaw6 = "raw6"
+	SocketProtocolUnix = "unix"
+)
+
+const unixAcceptConnections = 0x00010000
+
+var inetSocketProtocols = []string{
+	SocketProtocolTCP,
+	SocketProtocolTCP6,
+	SocketProtocolUDP,
+	SocketProtocolUDP6,
+	SocketProtocolRaw,
+	SocketProtocolRaw6,
+}
+
+var tcpStates = map[uint64]string{
+	0x01: "ESTABLISHED",
+	0x02: "SYN_SENT",
+	0x03: "SYN_RECV",
+	0x04: "FIN_WAIT1",
+	0x05: "FIN_WAIT2",
+	0x06: "TIME_WAIT",
+	0x07: "CLOSE",
+	0x08: "CLOSE_WAIT",
+	0x09: "LAST_ACK",
+	0x0A: "LISTEN",
+	0x0B: "CLOSING",
+	0x0C: "NEW_SYN_RECV",
+}
+
+var unixStates = map[uint64]string{
+	0x01: "UNCONNECTED",
+	0x02: "CONNECTING",
+	0x03: "CONNECTED",
+	0x04: "DISCONNECTING",
+}
+
+var unixTypes = map[uint64]string{
+	0x01: "stream",
+	0x02: "dgram",
+	0x05: "seqpacket",
+}
+
+var littleEndian = func() bool {
+	x := uint16(1)
+	return *(*byte)(unsafe.Pointer(&x)) == 1
+}()
+
+type SocketInfo struct {
+	Protocol      string
+	Type          string
+	LocalAddress  net.IP
+	LocalPort     uint64
+	RemoteAddress net.IP
+	RemotePort    uint64
+	Path          string
+	State         string
+	UserID        uint64
+	Inode         uint64
+	ProcessIDs    []uint64
+}
+
+func (si *SocketInfo) Listening() bool {
+	switch si.Protocol {
+	case SocketProtocolTCP, SocketProtocolTCP6, SocketProtocolUnix:
+		return si.State == "LISTEN"
+	}
+
+	// datagram and raw sockets receive data as soon as they are bound
+	return si.State == "UNCONN" && (si.LocalPort != 0 || si.Protocol == SocketProtocolRaw ||
+		si.Protocol == SocketProtocolRaw6)
+}
+
+func Sockets() ([]*SocketInfo, error) {
+	sockets, err := readSocketTables("/proc/net")
+	if err != nil {
+		return nil, err
+	}
+
+	pids, err := readProcessIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	owners := map[uint64][]uint64{}
+	for _, pid := range pids {
+		inodes, err := readProcSocketInodes(pid)
+		if err != nil {
+			continue
+		}
+
+		for _, inode := range inodes {
+			owners[inode] = append(owners[inode], pid)
+		}
+	}
+
+	for _, socket := range sockets {
+		socket.ProcessIDs = owners[socket.Inode]
+	}
+
+	return sockets, nil
+}
+
+func (pi *ProcessInfo) Sockets() ([]*SocketInfo, error) {
+	inodes, err := readProcSocketInodes(pi.ID)
+	if err != nil {
+		return nil, processError(pi.ID, err)
+	}
+	if len(inodes) == 0 {
+		return nil, nil
+	}
+
+	owned := map[uint64]bool{}
+	for _, inode := range inodes {
+		owned[inode] = true
+	}
+
+	// the socket tables of the process are read in order to look into its
+	// network namespace
+	all, err := readSocketTables(fmt.Sprintf(processFile, pi.ID, "net"))
+	if err != nil {
+		return nil, processError(pi.ID, err)
+	}
+
+	var sockets []*SocketInfo
+	for _, socket := range all {
+		if !owned[socket.Inode] {
+			continue
+		}
+
+		socket.ProcessIDs = []uint64{pi.ID}
+		sockets = append(sockets, socket)
+	}
+
+	return sockets, nil
+}
+
+func readSocketTables(dir string) ([]*SocketInfo, error) {
+	var sockets []*SocketInfo
+	for _, protocol := range inetSocketProtocols {
+		table, err := readInetSocketTable(filepath.Join(dir, protocol), protocol)
+		if err != nil {
+			// IPv6 tables are missing when IPv6 is disabled
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		sockets = append(sockets, table...)
+	}
+
+	table, err := readUnixSocketTable(filepath.Join(dir, "unix"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	return append(sockets, table...), nil
+}
+
+func readInetSocketTable(path, protocol string) ([]*SocketInfo, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var sockets []*SocketInfo
+
+	scanner := bufio.NewScanner(file)
+	for header := true; scanner.Scan(); header = false {
+		if header {
+			continue
+		}
+
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 {
+			return nil, ErrInvalidFileFormat
+		}
+
+		socket := &SocketInfo{Protocol: protocol}
+		if socket.LocalAddress, socket.LocalPort, err = parseSocketAddress(fields[1]); err != nil {
+			return nil, err
+		}
+
+		if socket.RemoteAddress, socket.RemotePort, err = parseSocketAddress(fields[2]); err != nil {
+			return nil, err
+		}
+
+		state, err := strconv.ParseUint(fields[3], 16, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		socket.State = tcpStates[state]
+		if state == 0x07 && !strings.HasPrefix(protocol, SocketProtocolTCP) {
+			socket.State = "UNCONN"
+		}
+
+		if socket.UserID, err = strconv.ParseUint(fields[7], 10, 64); err != nil {
+			return nil, err
+		}
+
+		if socket.Inode, err = strconv.ParseUint(fields[9], 10, 64); err != nil {
+			return nil, err
+		}
+
+		sockets = append(sockets, socket)
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return sockets, nil
+}
+
+func readUnixSocketTable(path string) ([]*SocketInfo, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var sockets []*SocketInfo
+
+	scanner := bufio.NewScanner(file)
+	for header := true; scanner.Scan(); header = false {
+		if header {
+			continue
+		}
+
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 7 {
+			return nil, ErrInvalidFileFormat
+		}
+
+		flags, err := strconv.ParseUint(fields[3], 16, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		socketType, err := strconv.ParseUint(fields[4], 16, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		state, err := strconv.ParseUint(fields[5], 16, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		socket := &SocketInfo{
+			Protocol: SocketProtocolUnix,
+			Type:     unixTypes[socketType],
+			State:    unixStates[state],
+			Path:     strings.Join(fields[7:], " "),
+		}
+		if flags&unixAcceptConnections != 0 {
+			socket.State = "LISTEN"
+		}
+
+		if socket.Inode, err = strconv.ParseUint(fields[6], 10, 64); err != nil {
+			return nil, err
+		}
+
+		sockets = append(sockets, socket)
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return sockets, nil
+}
+
+func parseSocketAddress(address string) (net.IP, uint64, error) {
+	parts := strings.Split(address, ":")
+	if len(parts) != 2 {
+		return nil, 0, ErrInvalidFileFormat
+	}
+
+	ip, err := hex.DecodeString(parts[0])
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(ip) != net.IPv4len && len(ip) != net.IPv6len {
+		return nil, 0, ErrInvalidFileFormat
+	}
+
+	// addresses are printed as 32 bit words in host byte order
+	if littleEndian {
+		for i := 0; i < len(ip); i += 4 {
+			ip[i], ip[i+1], ip[i+2], ip[i+3] = ip[i+3], ip[i+2], ip[i+1], ip[i]
+		}
+	}
+
+	port, err := strconv.ParseUint(parts[1], 16, 64)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return net.IP(ip), port, nil
+}
+
+func readProcSocketInodes(pid uint64) ([]uint64, error) {
+	names, err := readProcFDNames(pid)
+	if err != nil {
+		return nil, err
+	}
+
+	// a socket can be referenced by multiple descriptors of the process
+	var inodes []uint64
+	seen := map[uint64]bool{}
+	for _, name := range names {
+		target, err := os.Readlink(fmt.Sprintf(processFile, pid, "fd/"+name))
+		if err != nil {
+			continue
+		}
+
+		fileType, inode := parseFDTarget(target)
+		if fileType != FileTypeSocket || seen[inode] {
+			continue
+		}
+
+		seen[inode] = true
+		inodes = append(inodes, inode)
+	}
+
+	return inodes, nil
+}
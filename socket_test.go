@@ -0,0 +1,82 @@
+package sysinfo
+
+import (
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseSocketAddress(t *testing.T) {
+	if !littleEndian {
+		t.Skip("addresses below are in little endian word order")
+	}
+
+	tests := []struct {
+		address string
+		ip      net.IP
+		port    uint64
+		err     bool
+	}{
+		{"0100007F:0050", net.ParseIP("127.0.0.1"), 80, false},
+		{"00000000:0016", net.ParseIP("0.0.0.0"), 22, false},
+		{"0101A8C0:1F90", net.ParseIP("192.168.1.1"), 8080, false},
+		{"00000000000000000000000001000000:0016", net.ParseIP("::1"), 22, false},
+		{"B80D0120000000000000000001000000:01BB", net.ParseIP("2001:db8::1"), 443, false},
+		{"0000000000000000FFFF00000100007F:0035", net.ParseIP("127.0.0.1"), 53, false},
+		{"0100007F", nil, 0, true},
+		{"0100:0050", nil, 0, true},
+		{"ZZ00007F:0050", nil, 0, true},
+	}
+
+	for _, test := range tests {
+		ip, port, err := parseSocketAddress(test.address)
+		if (err != nil) != test.err {
+			t.Errorf("%s: unexpected error %v", test.address, err)
+			continue
+		}
+		if test.err {
+			continue
+		}
+
+		if !ip.Equal(test.ip) || port != test.port {
+			t.Errorf("%s: expected %s:%d, got %s:%d", test.address, test.ip, test.port, ip, port)
+		}
+	}
+}
+
+func TestReadUnixSocketTable(t *testing.T) {
+	content := "Num       RefCount Protocol Flags    Type St Inode Path\n" +
+		"0000000000000000: 00000002 00000000 00010000 0001 01 20173 /run/systemd/private\n" +
+		"0000000000000000: 00000003 00000000 00000000 0001 03 20514 /run/dbus/system bus\n" +
+		"0000000000000000: 00000002 00000000 00000000 0002 01 15840\n"
+
+	path := filepath.Join(t.TempDir(), "unix")
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sockets, err := readUnixSocketTable(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sockets) != 3 {
+		t.Fatalf("expected 3 sockets, got %d", len(sockets))
+	}
+
+	expected := []*SocketInfo{
+		{Protocol: SocketProtocolUnix, Type: "stream", State: "LISTEN", Path: "/run/systemd/private", Inode: 20173},
+		{Protocol: SocketProtocolUnix, Type: "stream", State: "CONNECTED", Path: "/run/dbus/system bus", Inode: 20514},
+		{Protocol: SocketProtocolUnix, Type: "dgram", State: "UNCONNECTED", Inode: 15840},
+	}
+	for i, socket := range sockets {
+		if !reflect.DeepEqual(socket, expected[i]) {
+			t.Errorf("expected %+v, got %+v", expected[i], socket)
+		}
+	}
+
+	if !sockets[0].Listening() || sockets[1].Listening() {
+		t.Errorf("only the socket accepting connections should be listening")
+	}
+}
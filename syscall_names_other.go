@@ -0,0 +1,8 @@
+//go:build !386 && !amd64 && !arm && !arm64
+// +build !386,!amd64,!arm,!arm64
+
+package sysinfo
+
+// the names of the architecture specific system calls are not known, only
+// the ones shared by all architectures can be resolved
+var syscallNames = map[uint64]string{}
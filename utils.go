@@ -1,9 +1,12 @@
 package sysinfo
 
 import (
+	"bufio"
 	"errors"
+	"io"
 	"io/ioutil"
 	"strings"
+	"sync"
 )
 
 // #include <unistd.h>
@@ -15,8 +18,19 @@ var (
 	ErrUserNotFound      = errors.New("user not found")
 	ErrGroupNotFound     = errors.New("group not found")
 	ErrInvalidFileFormat = errors.New("invalid file format")
+	ErrProcessNotFound   = errors.New("process not found")
+	ErrPermissionDenied  = errors.New("permission denied")
+	ErrInvalidInterval   = errors.New("invalid interval")
+	ErrInvalidQuery      = errors.New("invalid query")
 )
 
+var scanBufferPool = sync.Pool{
+	New: func() interface{} {
+		buf := make([]byte, 4096)
+		return &buf
+	},
+}
+
 func init() {
 	TicksPerSecond = uint64(C.sysconf(C._SC_CLK_TCK))
 }
@@ -29,3 +43,12 @@ func readSingleValueFile(path string) (string, error) {
 
 	return strings.TrimSpace(string(content)), nil
 }
+
+func newPooledScanner(r io.Reader) (*bufio.Scanner, func()) {
+	buf := scanBufferPool.Get().(*[]byte)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(*buf, bufio.MaxScanTokenSize)
+
+	return scanner, func() { scanBufferPool.Put(buf) }
+}